@@ -0,0 +1,116 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func validateBatchSize(config *dtos.ChaincodeConfig, size int) error {
+	if size > config.MaxBatchSize {
+		return fmt.Errorf("the batch has %d items, the maximum is %d", size, config.MaxBatchSize)
+	}
+
+	return nil
+}
+
+// getBatchIds returns the ids of the selection, either the given ids or the assets matching the filter
+func getBatchIds(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, encodedSelection string) ([]string, error) {
+	selection := &dtos.BatchSelection{}
+	err := json.Unmarshal([]byte(encodedSelection), selection)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the selection %s", err)
+	}
+
+	if (selection.Ids == nil) == (selection.Filter == nil) {
+		return nil, fmt.Errorf("the selection needs either ids or a filter")
+	}
+
+	if selection.Filter == nil {
+		clearAllStringFields(&selection.Ids)
+		if len(selection.Ids) == 0 {
+			return nil, fmt.Errorf("the batch is empty")
+		}
+
+		for _, id := range selection.Ids {
+			err = validateAssetId(id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = validateBatchSize(config, len(selection.Ids))
+		if err != nil {
+			return nil, err
+		}
+
+		return selection.Ids, nil
+	}
+
+	query, err := createQuery(selection.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryBatchIds(context, config, query)
+}
+
+// queryBatchIds runs the query without pagination since fabric only allows paginated queries in read only transactions
+func queryBatchIds(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, query string) ([]string, error) {
+	iterator, err := context.GetStub().GetQueryResult(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the ledger %s", err)
+	}
+	defer iterator.Close()
+
+	ids := []string{}
+	for iterator.HasNext() {
+		queryResponse, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+
+		if len(ids) == config.MaxBatchSize {
+			return nil, fmt.Errorf("the filter matches more than %d assets", config.MaxBatchSize)
+		}
+		ids = append(ids, queryResponse.Key)
+	}
+
+	return ids, nil
+}
+
+func newBatchResult() *dtos.BatchResult {
+	return &dtos.BatchResult{Items: []*dtos.BatchItemResult{}}
+}
+
+func addBatchFailure(result *dtos.BatchResult, id string, err error) {
+	result.Items = append(result.Items, &dtos.BatchItemResult{Id: id, Error: err.Error()})
+	result.Failed++
+}
+
+func addBatchSuccess(result *dtos.BatchResult, id string, changedFields []string) *dtos.BatchItemResult {
+	item := &dtos.BatchItemResult{Id: id, Success: true, ChangedFields: changedFields}
+	result.Items = append(result.Items, item)
+	result.Succeeded++
+	return item
+}
+
+func getSucceededIds(result *dtos.BatchResult) []string {
+	ids := []string{}
+	for _, item := range result.Items {
+		if item.Success {
+			ids = append(ids, item.Id)
+		}
+	}
+	return ids
+}
+
+func encodeBatchResult(result *dtos.BatchResult) (string, error) {
+	resultEncoded, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the batch result %s", err)
+	}
+
+	return string(resultEncoded), nil
+}
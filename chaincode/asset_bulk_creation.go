@@ -0,0 +1,154 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func (s *SmartContract) CreateAssets(context contractapi.TransactionContextInterface, encodedValues string) (string, error) {
+	_, err := s.authorize(context, submitterRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	requests, duplicates, err := s.validateAssets(context, encodedValues)
+	if err != nil {
+		return "", err
+	}
+
+	assets := []*dtos.AssetRequest{}
+	ids := []string{}
+	for _, request := range requests {
+		asset, err := s.postAsset(context, request, "")
+		if err != nil {
+			return "", err
+		}
+		assets = append(assets, asset)
+		ids = append(ids, asset.Id)
+	}
+
+	err = emitAssetBatchEventWithDuplicates(context, formsCreatedEvent, ids, duplicates)
+	if err != nil {
+		return "", err
+	}
+
+	assetsEncoded, err := json.Marshal(assets)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the assets %s", err)
+	}
+
+	return string(assetsEncoded), nil
+}
+
+// validateAssets checks every item before writing anything so the batch is either created or rejected as a whole,
+// it also returns the duplicated hashes found under the warn policy by id
+func (s *SmartContract) validateAssets(context contractapi.TransactionContextInterface, encodedValues string) ([]*dtos.PostAssetRequest, map[string][]string, error) {
+	encodedRequests := []json.RawMessage{}
+	err := json.Unmarshal([]byte(encodedValues), &encodedRequests)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding the given values results in: %s", err)
+	}
+
+	if len(encodedRequests) == 0 {
+		return nil, nil, fmt.Errorf("the batch is empty")
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateBatchSize(config, len(encodedRequests))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requests := []*dtos.PostAssetRequest{}
+	duplicates := map[string][]string{}
+	itemErrors := []*dtos.BatchItemError{}
+	batchIds := map[string]int{}
+	batchHashes := map[string][]string{}
+	for index, encodedRequest := range encodedRequests {
+		request, err := s.validateAsset(string(encodedRequest))
+		if err != nil {
+			itemErrors = append(itemErrors, &dtos.BatchItemError{Index: index, Error: err.Error()})
+			continue
+		}
+
+		duplicateIds, itemError := s.validateBatchItem(context, config, request, batchIds, batchHashes)
+		if itemError != "" {
+			itemErrors = append(itemErrors, &dtos.BatchItemError{Index: index, Id: request.Id, Error: itemError})
+			continue
+		}
+
+		if len(duplicateIds) != 0 {
+			duplicates[request.Id] = duplicateIds
+		}
+		batchIds[request.Id] = index
+		batchHashes[request.Hash] = append(batchHashes[request.Hash], request.Id)
+		requests = append(requests, request)
+	}
+
+	if len(itemErrors) != 0 {
+		encodedErrors, err := json.Marshal(itemErrors)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error encoding the batch errors %s", err)
+		}
+		return nil, nil, fmt.Errorf("the batch was rejected %s", encodedErrors)
+	}
+
+	if len(duplicates) == 0 {
+		duplicates = nil
+	}
+
+	return requests, duplicates, nil
+}
+
+// validateBatchItem returns the ids of the assets already holding the hash, from the ledger or an earlier item
+// of the batch, when the duplicate policy is warn
+func (s *SmartContract) validateBatchItem(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, request *dtos.PostAssetRequest, batchIds map[string]int, batchHashes map[string][]string) ([]string, string) {
+	if request.IdempotencyKey != "" {
+		return nil, "the idempotency key is not supported in a batch"
+	}
+
+	if index, ok := batchIds[request.Id]; ok {
+		return nil, fmt.Sprintf("the id is repeated in the item %d", index)
+	}
+
+	if s.exists(context, request.Id) {
+		return nil, "already exists"
+	}
+
+	err := validateFieldsSize(config, request.Fields)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	err = validateAssetFormType(context, config, request.TypeForm, request.Fields)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	err = validateInsertionType(context, config, request.InsertionType)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	if config.DuplicateHashPolicy == allowDuplicateHashPolicy {
+		return nil, ""
+	}
+
+	batchDuplicateIds := batchHashes[request.Hash]
+	if len(batchDuplicateIds) != 0 && config.DuplicateHashPolicy == rejectDuplicateHashPolicy {
+		return nil, fmt.Sprintf("the hash is repeated in the item %d", batchIds[batchDuplicateIds[0]])
+	}
+
+	duplicateIds, err := s.checkDuplicateHash(context, config, request.Hash, request.Id)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return append(duplicateIds, batchDuplicateIds...), ""
+}
@@ -0,0 +1,79 @@
+package chaincode
+
+import (
+	"fmt"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+// DeleteAssets deletes every selected asset with the configured delete mode, the assets that can't be deleted
+// are reported in the result while the others are still deleted
+func (s *SmartContract) DeleteAssets(context contractapi.TransactionContextInterface, encodedSelection string, reason string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	deleteMode := config.DeleteMode
+	if deleteMode == softDeleteMode && !utils.IsValidString(strings.TrimSpace(reason)) {
+		return "", fmt.Errorf("the reason is not valid")
+	}
+
+	ids, err := getBatchIds(context, config, encodedSelection)
+	if err != nil {
+		return "", err
+	}
+
+	result := newBatchResult()
+	deletedIds := map[string]bool{}
+	for _, id := range ids {
+		if deletedIds[id] {
+			addBatchFailure(result, id, fmt.Errorf("the id is repeated"))
+			continue
+		}
+		deletedIds[id] = true
+
+		changedFields, err := s.deleteBatchItem(context, deleteMode, id, reason)
+		if err != nil {
+			addBatchFailure(result, id, err)
+			continue
+		}
+		addBatchSuccess(result, id, changedFields)
+	}
+
+	if result.Succeeded != 0 {
+		err = emitAssetBatchEvent(context, formsDeletedEvent, getSucceededIds(result))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return encodeBatchResult(result)
+}
+
+func (s *SmartContract) deleteBatchItem(context contractapi.TransactionContextInterface, deleteMode string, id string, reason string) ([]string, error) {
+	if !utils.IsValidString(id) {
+		return nil, fmt.Errorf("the id is not valid")
+	}
+
+	if !s.exists(context, id) {
+		return nil, fmt.Errorf("the asset doesn't exist")
+	}
+
+	if deleteMode == softDeleteMode {
+		return s.softDeleteAsset(context, id, reason)
+	}
+
+	_, err := s.deleteDataFromLedgerById(context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,103 @@
+package chaincode
+
+import (
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// PatchAssets applies the same patch to every selected asset, the assets that can't be patched are reported
+// in the result while the others are still written, an error while writing fails the whole batch
+func (s *SmartContract) PatchAssets(context contractapi.TransactionContextInterface, encodedSelection string, encodedData string) (string, error) {
+	callerRole, err := s.authorize(context, editorRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := decodePatchRequest(encodedData)
+	if err != nil {
+		return "", err
+	}
+
+	if request.ExpectedVersion != nil {
+		return "", fmt.Errorf("the expected version is not supported in a batch")
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	ids, err := getBatchIds(context, config, encodedSelection)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateBatchHash(config, request, ids)
+	if err != nil {
+		return "", err
+	}
+
+	result := newBatchResult()
+	patchedIds := map[string]bool{}
+	for _, id := range ids {
+		if patchedIds[id] {
+			addBatchFailure(result, id, fmt.Errorf("the id is repeated"))
+			continue
+		}
+		patchedIds[id] = true
+
+		patch, err := s.patchBatchItem(context, config, callerRole, request, id)
+		if err != nil {
+			addBatchFailure(result, id, err)
+			continue
+		}
+
+		err = writePatch(context, patch)
+		if err != nil {
+			return "", err
+		}
+		addBatchSuccess(result, id, patch.changedFields).DuplicateIds = patch.duplicateIds
+	}
+
+	if result.Succeeded != 0 {
+		err = emitAssetBatchEvent(context, formsPatchedEvent, getSucceededIds(result))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return encodeBatchResult(result)
+}
+
+// validateBatchHash rejects giving the same hash to several assets when the duplicates are rejected, the hash
+// index doesn't show the writes of the current transaction so they can't be checked item by item
+func validateBatchHash(config *dtos.ChaincodeConfig, request *dtos.PutAssetRequest, ids []string) error {
+	if request.Hash == "" || len(ids) < 2 {
+		return nil
+	}
+
+	if config.DuplicateHashPolicy == rejectDuplicateHashPolicy {
+		return fmt.Errorf("the hash can't be patched in several assets when the duplicates are rejected")
+	}
+
+	return nil
+}
+
+func (s *SmartContract) patchBatchItem(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, callerRole role, request *dtos.PutAssetRequest, id string) (*patchedAsset, error) {
+	if !utils.IsValidString(id) {
+		return nil, fmt.Errorf("the id is not valid")
+	}
+
+	if !s.exists(context, id) {
+		return nil, fmt.Errorf("the asset doesn't exist")
+	}
+
+	err := s.ensureOwnerOrAdmin(context, callerRole, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.preparePatch(context, config, request, id)
+}
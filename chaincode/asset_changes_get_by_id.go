@@ -0,0 +1,98 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	createOperation  = "create"
+	updateOperation  = "update"
+	deleteOperation  = "delete"
+	restoreOperation = "restore"
+)
+
+func (s *SmartContract) GetAssetChangesById(context contractapi.TransactionContextInterface, id string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	cleanId := utils.RemoveStringSpaces(id)
+	if !utils.IsValidString(cleanId) {
+		return "", fmt.Errorf("the id is not valid")
+	}
+
+	assetHistory, err := GetHistoryFromCleanKey(context, cleanId)
+	if err != nil {
+		return "", err
+	}
+
+	if len(assetHistory) == 0 {
+		return "", fmt.Errorf("the asset doesn't exist")
+	}
+
+	decodedHistory, err := decodeHistory(assetHistory)
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := getAssetChanges(decodedHistory)
+	if err != nil {
+		return "", err
+	}
+
+	changesEncoded, err := json.Marshal(changes)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the changes %s", err)
+	}
+
+	return string(changesEncoded), nil
+}
+
+// getAssetChanges compares every version with the previous one, fabric returns the history newest first
+// so it is walked backwards to return the changes in the order they happened
+func getAssetChanges(history []*dtos.AssetHistoryEntry) ([]*dtos.AssetChange, error) {
+	changes := []*dtos.AssetChange{}
+	var previousAsset *dtos.AssetRequest
+	for i := len(history) - 1; i >= 0; i-- {
+		entry := history[i]
+		fieldChanges, err := getFieldChanges(previousAsset, entry.Asset)
+		if err != nil {
+			return nil, err
+		}
+
+		change := &dtos.AssetChange{
+			TxId:      entry.TxId,
+			Timestamp: entry.Timestamp,
+			Operation: getChangeOperation(previousAsset, entry),
+			Changes:   fieldChanges,
+		}
+
+		if entry.Asset != nil && utils.IsValidString(entry.Asset.UpdatedBy.MspId) {
+			submitter := entry.Asset.UpdatedBy
+			change.Submitter = &submitter
+		}
+
+		changes = append(changes, change)
+		previousAsset = entry.Asset
+	}
+
+	return changes, nil
+}
+
+func getChangeOperation(previousAsset *dtos.AssetRequest, entry *dtos.AssetHistoryEntry) string {
+	switch {
+	case entry.IsDelete:
+		return deleteOperation
+	case previousAsset == nil:
+		return createOperation
+	case previousAsset.Deleted:
+		return restoreOperation
+	default:
+		return updateOperation
+	}
+}
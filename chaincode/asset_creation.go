@@ -10,16 +10,90 @@ import (
 )
 
 func (s *SmartContract) CreateAsset(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
-	newDto, err := s.validateAsset(context, encodedValue)
+	_, err := s.authorize(context, submitterRoles...)
 	if err != nil {
 		return "", err
 	}
 
-	asset, err := s.postAsset(context, newDto)
+	newDto, err := s.validateAsset(encodedValue)
 	if err != nil {
 		return "", err
 	}
 
+	replayedAsset, err := s.replayCreateAsset(context, newDto)
+	if err != nil {
+		return "", err
+	}
+
+	if replayedAsset != nil {
+		return encodeCreatedAsset(replayedAsset)
+	}
+
+	if s.exists(context, newDto.Id) {
+		return "", fmt.Errorf("already exists")
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateFieldsSize(config, newDto.Fields)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateAssetFormType(context, config, newDto.TypeForm, newDto.Fields)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateInsertionType(context, config, newDto.InsertionType)
+	if err != nil {
+		return "", err
+	}
+
+	duplicateIds, err := s.checkDuplicateHash(context, config, newDto.Hash, newDto.Id)
+	if err != nil {
+		return "", err
+	}
+
+	privateDetails, privateCollection, err := readPrivateDetails(context, config)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := s.postAsset(context, newDto, privateCollection)
+	if err != nil {
+		return "", err
+	}
+
+	if privateDetails != nil {
+		err = savePrivateDetails(context, privateCollection, asset.Id, privateDetails)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = s.saveIdempotencyRecord(context, newDto, asset)
+	if err != nil {
+		return "", err
+	}
+
+	changedFields, err := getChangedFields(nil, asset)
+	if err != nil {
+		return "", err
+	}
+
+	err = emitAssetEventWithDuplicates(context, formCreatedEvent, asset.Id, changedFields, duplicateIds)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeCreatedAsset(asset)
+}
+
+func encodeCreatedAsset(asset *dtos.AssetRequest) (string, error) {
 	assetEncoded, err := json.Marshal(asset)
 	if err != nil {
 		return "", fmt.Errorf("error encoding the asset %s", err.Error())
@@ -28,21 +102,46 @@ func (s *SmartContract) CreateAsset(context contractapi.TransactionContextInterf
 	return string(assetEncoded), nil
 }
 
-func (s *SmartContract) postAsset(context contractapi.TransactionContextInterface, cleanDto *dtos.PostAssetRequest) (*dtos.PostAssetRequest, error) {
-	encodedCleanDto, err := json.Marshal(cleanDto)
+// postAsset stores the asset, privateCollection is only set when its private details are stored in that collection
+func (s *SmartContract) postAsset(context contractapi.TransactionContextInterface, cleanDto *dtos.PostAssetRequest, privateCollection string) (*dtos.AssetRequest, error) {
+	asset := &dtos.AssetRequest{
+		Id:                cleanDto.Id,
+		TypeForm:          cleanDto.TypeForm,
+		Description:       cleanDto.Description,
+		Timestamp:         cleanDto.Timestamp,
+		InsertionType:     cleanDto.InsertionType,
+		Hash:              cleanDto.Hash,
+		HashAlgorithm:     cleanDto.HashAlgorithm,
+		Fields:            cleanDto.Fields,
+		PrivateCollection: privateCollection,
+	}
+
+	err := stampAssetUpdate(context, asset)
+	if err != nil {
+		return nil, err
+	}
+	asset.Owner = asset.UpdatedBy
+	asset.RecordedAt = asset.UpdatedAt
+
+	encodedAsset, err := json.Marshal(asset)
 	if err != nil {
 		return nil, fmt.Errorf("encoding cleaned object %s", err)
 	}
 
-	err = context.GetStub().PutState(cleanDto.Id, encodedCleanDto)
+	err = context.GetStub().PutState(asset.Id, encodedAsset)
 	if err != nil {
 		return nil, fmt.Errorf("inserting cleaned object %s", err)
 	}
 
-	return cleanDto, nil
+	err = putHashIndex(context, asset.Hash, asset.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
 }
 
-func (s *SmartContract) validateAsset(context contractapi.TransactionContextInterface, value string) (*dtos.PostAssetRequest, error) {
+func (s *SmartContract) validateAsset(value string) (*dtos.PostAssetRequest, error) {
 	newDto, err := utils.DecodeValueToPostRequest(value)
 	if err != nil {
 		return nil, fmt.Errorf("decoding the given value results in: %s", err)
@@ -52,9 +151,21 @@ func (s *SmartContract) validateAsset(context contractapi.TransactionContextInte
 		return nil, fmt.Errorf("some fields are not valid")
 	}
 
-	if s.exists(context, newDto.Id) {
-		return nil, fmt.Errorf("already exists")
+	err = validateAssetId(newDto.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	newDto.HashAlgorithm, newDto.Hash, err = normalizeHash(newDto.HashAlgorithm, newDto.Hash)
+	if err != nil {
+		return nil, err
 	}
+
+	err = validateFieldKeys(newDto.Fields)
+	if err != nil {
+		return nil, err
+	}
+
 	return newDto, nil
 }
 
@@ -63,6 +174,7 @@ func removeSpacesAndArePostRequestFieldsValid(request *dtos.PostAssetRequest) bo
 	request.TypeForm = utils.RemoveStringSpaces(request.TypeForm)
 	request.InsertionType = utils.RemoveStringSpaces(request.InsertionType)
 	request.Hash = utils.RemoveStringSpaces(request.Hash)
+	request.IdempotencyKey = utils.RemoveStringSpaces(request.IdempotencyKey)
 
 	return areAllPostFieldsValid(
 		request.Id,
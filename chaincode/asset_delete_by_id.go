@@ -1,18 +1,72 @@
 package chaincode
 
 import (
+	"encoding/json"
 	"fmt"
+	"form-chaincode/dtos"
 	"form-chaincode/utils"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
 )
 
-func (s *SmartContract) DeleteAssetById(context contractapi.TransactionContextInterface, id string) (bool, error) {
+const (
+	hardDeleteMode = "hard"
+	softDeleteMode = "soft"
+)
+
+func (s *SmartContract) DeleteAssetById(context contractapi.TransactionContextInterface, id string, reason string, expectedVersion string) (bool, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return false, err
+	}
+
 	clearId, err := s.validateDataDeleteById(context, id)
 	if err != nil {
 		return false, err
 	}
 
-	return s.deleteDataFromLedgerById(context, clearId)
+	version, err := parseExpectedVersion(expectedVersion)
+	if err != nil {
+		return false, err
+	}
+
+	if version != nil {
+		err = s.ensureExpectedVersion(context, clearId, version)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return false, err
+	}
+
+	if config.DeleteMode == softDeleteMode {
+		changedFields, err := s.softDeleteAsset(context, clearId, reason)
+		if err != nil {
+			return false, err
+		}
+
+		err = emitAssetEvent(context, formDeletedEvent, clearId, changedFields)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	deleted, err := s.deleteDataFromLedgerById(context, clearId)
+	if err != nil {
+		return false, err
+	}
+
+	err = emitAssetEvent(context, formDeletedEvent, clearId, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
 }
 
 func (s *SmartContract) validateDataDeleteById(context contractapi.TransactionContextInterface, id string) (string, error) {
@@ -28,11 +82,78 @@ func (s *SmartContract) validateDataDeleteById(context contractapi.TransactionCo
 	return clearId, nil
 }
 
+func (s *SmartContract) ensureExpectedVersion(context contractapi.TransactionContextInterface, clearId string, expectedVersion *int) error {
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return err
+	}
+
+	return checkExpectedVersion(asset, expectedVersion)
+}
+
 func (s *SmartContract) deleteDataFromLedgerById(context contractapi.TransactionContextInterface, clearId string) (bool, error) {
-	err := context.GetStub().DelState(clearId)
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return false, err
+	}
+
+	err = context.GetStub().DelState(clearId)
 	if err != nil {
 		return false, fmt.Errorf("error deleting state from the ledger %s", err)
 	}
 
+	err = deleteHashIndex(context, asset.Hash, clearId)
+	if err != nil {
+		return false, err
+	}
+
+	if utils.IsValidString(asset.PrivateCollection) {
+		err = context.GetStub().DelPrivateData(asset.PrivateCollection, clearId)
+		if err != nil {
+			return false, fmt.Errorf("error deleting the private details %s", err)
+		}
+	}
+
 	return true, nil
 }
+
+func (s *SmartContract) softDeleteAsset(context contractapi.TransactionContextInterface, clearId string, reason string) ([]string, error) {
+	reason = strings.TrimSpace(reason)
+	if !utils.IsValidString(reason) {
+		return nil, fmt.Errorf("the reason is not valid")
+	}
+
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Deleted {
+		return nil, fmt.Errorf("the asset is already deleted")
+	}
+	previousAsset := *asset
+
+	err = stampAssetUpdate(context, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	asset.Deleted = true
+	asset.Deletion = &dtos.Deletion{
+		Reason:    reason,
+		DeletedBy: asset.UpdatedBy,
+		DeletedAt: asset.UpdatedAt,
+	}
+
+	encodedData, err := json.Marshal(asset)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding asset after deleting it %s", err)
+	}
+
+	err = context.GetStub().PutState(clearId, encodedData)
+	if err != nil {
+		return nil, fmt.Errorf("error updating ledger %s", err)
+	}
+
+	return getChangedFields(&previousAsset, asset)
+}
@@ -0,0 +1,180 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"reflect"
+	"sort"
+)
+
+const (
+	formCreatedEvent              = "FormCreated"
+	formPatchedEvent              = "FormPatched"
+	formDeletedEvent              = "FormDeleted"
+	formOwnershipTransferredEvent = "FormOwnershipTransferred"
+	formRestoredEvent             = "FormRestored"
+	formsCreatedEvent             = "FormsCreated"
+	formsPatchedEvent             = "FormsPatched"
+	formsDeletedEvent             = "FormsDeleted"
+)
+
+// assetEventVersion is increased whenever the event payload changes in a non compatible way
+const assetEventVersion = 1
+
+// emitAssetEvent sets the chaincode event of the transaction, fabric only keeps one event per transaction
+func emitAssetEvent(context contractapi.TransactionContextInterface, name string, id string, changedFields []string) error {
+	return emitAssetEventWithDuplicates(context, name, id, changedFields, nil)
+}
+
+// emitAssetEventWithDuplicates also reports the other assets holding the same hash when the duplicate policy is warn
+func emitAssetEventWithDuplicates(context contractapi.TransactionContextInterface, name string, id string, changedFields []string, duplicateIds []string) error {
+	submitter, err := getCallerIdentity(context)
+	if err != nil {
+		return err
+	}
+
+	if changedFields == nil {
+		changedFields = []string{}
+	}
+
+	event := &dtos.AssetEvent{
+		Version:       assetEventVersion,
+		Id:            id,
+		ChangedFields: changedFields,
+		DuplicateIds:  duplicateIds,
+		TxId:          context.GetStub().GetTxID(),
+		Submitter:     *submitter,
+	}
+
+	encodedEvent, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding the event %s", err)
+	}
+
+	err = context.GetStub().SetEvent(name, encodedEvent)
+	if err != nil {
+		return fmt.Errorf("error setting the event %s", err)
+	}
+
+	return nil
+}
+
+// emitAssetBatchEvent is the event of the transactions that write several assets at once
+func emitAssetBatchEvent(context contractapi.TransactionContextInterface, name string, ids []string) error {
+	return emitAssetBatchEventWithDuplicates(context, name, ids, nil)
+}
+
+// emitAssetBatchEventWithDuplicates also reports, by id, the other assets holding the same hash when the duplicate
+// policy is warn
+func emitAssetBatchEventWithDuplicates(context contractapi.TransactionContextInterface, name string, ids []string, duplicates map[string][]string) error {
+	submitter, err := getCallerIdentity(context)
+	if err != nil {
+		return err
+	}
+
+	event := &dtos.AssetBatchEvent{
+		Version:    assetEventVersion,
+		Ids:        ids,
+		Duplicates: duplicates,
+		TxId:       context.GetStub().GetTxID(),
+		Submitter:  *submitter,
+	}
+
+	encodedEvent, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding the event %s", err)
+	}
+
+	err = context.GetStub().SetEvent(name, encodedEvent)
+	if err != nil {
+		return fmt.Errorf("error setting the event %s", err)
+	}
+
+	return nil
+}
+
+// bookkeepingFields are maintained by the chaincode on every write and are not reported as changes
+var bookkeepingFields = map[string]bool{
+	"doc_type":    true,
+	"recorded_at": true,
+	"updated_at":  true,
+	"updated_by":  true,
+	"version":     true,
+}
+
+// getChangedFields returns the sorted json names of the top level fields that differ between both versions
+func getChangedFields(before *dtos.AssetRequest, after *dtos.AssetRequest) ([]string, error) {
+	fieldChanges, err := getFieldChanges(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	changedFields := []string{}
+	for _, fieldChange := range fieldChanges {
+		changedFields = append(changedFields, fieldChange.Field)
+	}
+
+	return changedFields, nil
+}
+
+// getFieldChanges returns the old and new values of the top level fields that differ, sorted by json name
+func getFieldChanges(before *dtos.AssetRequest, after *dtos.AssetRequest) ([]dtos.FieldChange, error) {
+	beforeFields, err := encodeAssetToMap(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := encodeAssetToMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	for field := range beforeFields {
+		fields[field] = true
+	}
+	for field := range afterFields {
+		fields[field] = true
+	}
+
+	fieldChanges := []dtos.FieldChange{}
+	for field := range fields {
+		if bookkeepingFields[field] {
+			continue
+		}
+
+		if !reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			fieldChanges = append(fieldChanges, dtos.FieldChange{
+				Field:    field,
+				OldValue: beforeFields[field],
+				NewValue: afterFields[field],
+			})
+		}
+	}
+
+	sort.Slice(fieldChanges, func(i, j int) bool {
+		return fieldChanges[i].Field < fieldChanges[j].Field
+	})
+	return fieldChanges, nil
+}
+
+func encodeAssetToMap(asset *dtos.AssetRequest) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if asset == nil {
+		return fields, nil
+	}
+
+	encodedAsset, err := json.Marshal(asset)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the asset %s", err)
+	}
+
+	err = json.Unmarshal(encodedAsset, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the asset %s", err)
+	}
+
+	return fields, nil
+}
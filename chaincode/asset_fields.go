@@ -0,0 +1,98 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"regexp"
+	"sort"
+)
+
+var fieldKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validateFieldKeys checks every key of the fields, nested objects included, so they can be used in a field path
+func validateFieldKeys(fields map[string]interface{}) error {
+	for key, value := range fields {
+		if !fieldKeyPattern.MatchString(key) {
+			return fmt.Errorf("the field key %s is not valid", key)
+		}
+
+		nestedFields, isObject := value.(map[string]interface{})
+		if isObject {
+			err := validateFieldKeys(nestedFields)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateFieldsSize(config *dtos.ChaincodeConfig, fields map[string]interface{}) error {
+	encodedFields, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("error encoding the fields %s", err)
+	}
+
+	if len(encodedFields) > config.MaxFieldsSize {
+		return fmt.Errorf("the fields are %d bytes, the maximum is %d", len(encodedFields), config.MaxFieldsSize)
+	}
+
+	return nil
+}
+
+// mergeFields returns a copy of the stored fields with the patched ones, a null value removes the field
+func mergeFields(storedFields map[string]interface{}, patchedFields map[string]interface{}) map[string]interface{} {
+	mergedFields := map[string]interface{}{}
+	for key, value := range storedFields {
+		mergedFields[key] = value
+	}
+
+	for key, value := range patchedFields {
+		if value == nil {
+			delete(mergedFields, key)
+			continue
+		}
+		mergedFields[key] = value
+	}
+
+	if len(mergedFields) == 0 {
+		return nil
+	}
+	return mergedFields
+}
+
+var fieldPathPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// createFieldsClause matches every field path of the filter with its value, the paths are sorted to keep the
+// query deterministic
+func createFieldsClause(fieldsFilter map[string]interface{}) (string, error) {
+	paths := []string{}
+	for path := range fieldsFilter {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fieldsClause := ""
+	for _, path := range paths {
+		if !fieldPathPattern.MatchString(path) {
+			return "", fmt.Errorf("the field path %s is not valid", path)
+		}
+
+		value := fieldsFilter[path]
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return "", fmt.Errorf("the field path %s can only be compared with a string, a number or a boolean", path)
+		}
+
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("error encoding the field value %s", err)
+		}
+		fieldsClause += `"fields.` + path + `":` + string(encodedValue) + `,`
+	}
+
+	return fieldsClause, nil
+}
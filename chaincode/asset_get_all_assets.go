@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"form-chaincode/dtos"
 	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"strings"
 )
@@ -15,41 +16,75 @@ func (s *SmartContract) GetAllAssets(
 	sizeSize string,
 	filter string,
 ) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
 	page, size, err := validateDataGetAllAssets(pageSize, sizeSize)
 	if err != nil {
 		return "", err
 	}
 
-	query, err := createQuery(filter)
+	filterDecoded, err := decodeFilter(filter)
 	if err != nil {
 		return "", err
 	}
 
-	assets, err := s.queryAllSetsWithPagination(context, query, page, size)
+	query, err := createQuery(filterDecoded)
 	if err != nil {
 		return "", err
 	}
 
-	encodedAssets, err := json.Marshal(assets)
+	assets, bookmark, err := s.queryAllSetsWithPagination(context, query, page, size)
+	if err != nil {
+		return "", err
+	}
+
+	response := &dtos.GetAllAssetsResponse{
+		Items:    assets,
+		Page:     page,
+		Size:     size,
+		HasMore:  len(assets) == size && bookmark != "",
+		Bookmark: bookmark,
+	}
+
+	if filterDecoded.IncludeTotal {
+		total, err := countAssets(context, query)
+		if err != nil {
+			return "", err
+		}
+		response.Total = &total
+		response.HasMore = (page+1)*size < total
+	}
+
+	encodedResponse, err := json.Marshal(response)
 	if err != nil {
 		return "", fmt.Errorf("error encoding the final result %s", err)
 	}
 
-	return string(encodedAssets), nil
+	return string(encodedResponse), nil
 }
 
-func createQuery(filter string) (string, error) {
+func decodeFilter(filter string) (*dtos.Filter, error) {
 	filterByte := []byte(filter)
 
 	filterDecoded := &dtos.Filter{}
 	err := json.Unmarshal(filterByte, filterDecoded)
 	if err != nil {
-		return "", fmt.Errorf("error decoding filter %s", err)
+		return nil, fmt.Errorf("error decoding filter %s", err)
 	}
 
-	mainQuery := `{"selector":{`
-	initQueryLen := len(mainQuery)
-	cleanFilter(filterDecoded)
+	return filterDecoded, nil
+}
+
+func createQuery(filterDecoded *dtos.Filter) (string, error) {
+	mainQuery := `{"selector":{` + createLegacyClause(filterDecoded.IncludeDeleted) + `,`
+	selectorFields := map[string]bool{"doc_type": true, "deleted": !filterDecoded.IncludeDeleted}
+	err := cleanFilter(filterDecoded)
+	if err != nil {
+		return "", err
+	}
 
 	if filterDecoded.Hashs != nil {
 		encodedArr, err := encodeArray(filterDecoded.Hashs)
@@ -58,6 +93,7 @@ func createQuery(filter string) (string, error) {
 		}
 		queryToAdd := `"hash":{"$in":` + string(encodedArr) + `},`
 		mainQuery += queryToAdd
+		selectorFields["hash"] = true
 	}
 
 	if filterDecoded.TypeForms != nil {
@@ -67,6 +103,7 @@ func createQuery(filter string) (string, error) {
 		}
 		queryToAdd := `"type_form":{"$in":` + string(encodedArr) + `},`
 		mainQuery += queryToAdd
+		selectorFields["type_form"] = true
 	}
 
 	if filterDecoded.InsertionTypes != nil {
@@ -76,6 +113,7 @@ func createQuery(filter string) (string, error) {
 		}
 		queryToAdd := `"insertion_type":{"$in":` + string(encodedArr) + `},`
 		mainQuery += queryToAdd
+		selectorFields["insertion_type"] = true
 	}
 
 	if filterDecoded.Ids != nil {
@@ -85,6 +123,7 @@ func createQuery(filter string) (string, error) {
 		}
 		queryToAdd := `"id":{"$in":` + string(encodedArr) + `},`
 		mainQuery += queryToAdd
+		selectorFields["id"] = true
 	}
 
 	isValid, err := isTimeFilterValid(&filterDecoded.TimeFilter)
@@ -93,6 +132,11 @@ func createQuery(filter string) (string, error) {
 	}
 
 	if isValid {
+		timeField, err := getTimeFilterField(&filterDecoded.TimeFilter)
+		if err != nil {
+			return "", err
+		}
+
 		minimumEncoded, err := json.Marshal(filterDecoded.TimeFilter.Min)
 		if err != nil {
 			return "", err
@@ -101,18 +145,144 @@ func createQuery(filter string) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		queryToAdd := `"timestamp":{"$gte":` + string(minimumEncoded) + `,` + `"$lte":` + string(maximumEncoded) + `},`
+		queryToAdd := `"` + timeField + `":{"$gte":` + string(minimumEncoded) + `,` + `"$lte":` + string(maximumEncoded) + `},`
 		mainQuery += queryToAdd
+		selectorFields[timeField] = true
 	}
 
-	if len(mainQuery) != initQueryLen {
-		mainQuery = strings.TrimSuffix(mainQuery, ",")
+	if len(filterDecoded.Fields) != 0 {
+		fieldsClause, err := createFieldsClause(filterDecoded.Fields)
+		if err != nil {
+			return "", err
+		}
+		mainQuery += fieldsClause
+	}
+
+	sortClause, err := createSortClause(filterDecoded.Sort)
+	if err != nil {
+		return "", err
 	}
 
-	mainQuery += `}}`
+	if sortClause != "" && !isAnySortFieldInSelector(filterDecoded.Sort, selectorFields) {
+		// couchdb only sorts when at least one of the sort fields is part of the selector
+		mainQuery += `"` + filterDecoded.Sort[0].Field + `":{"$gt":null},`
+	}
+
+	mainQuery = strings.TrimSuffix(mainQuery, ",")
+
+	mainQuery += `}`
+	mainQuery += sortClause
+	mainQuery += `}`
 	return mainQuery, nil
 }
 
+// createLegacyClause also selects the assets written before doc_type and deleted existed since couchdb only
+// matches a missing field with $exists, the clauses are combined with $and because a selector has a single $or
+func createLegacyClause(includeDeleted bool) string {
+	docTypeClause := `"$or":[{"doc_type":"` + assetDocType + `"},{"doc_type":{"$exists":false}}]`
+	if includeDeleted {
+		return docTypeClause
+	}
+
+	return `"$and":[{` + docTypeClause + `},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]`
+}
+
+var sortableFields = map[string]bool{
+	"timestamp":      true,
+	"type_form":      true,
+	"insertion_type": true,
+	"id":             true,
+}
+
+// sortIndexes are the fields of the indexes shipped in deploy/META-INF, couchdb fails a sort without an index
+// of the same fields in the same order
+var sortIndexes = [][]string{
+	{"timestamp"},
+	{"type_form"},
+	{"insertion_type"},
+	{"id"},
+	{"type_form", "timestamp"},
+	{"insertion_type", "timestamp"},
+}
+
+func createSortClause(sortFields []dtos.SortField) (string, error) {
+	if len(sortFields) == 0 {
+		return "", nil
+	}
+
+	sortClause := `,"sort":[`
+	usedFields := map[string]bool{}
+	for i := range sortFields {
+		sortField := &sortFields[i]
+		sortField.Field = utils.RemoveStringSpaces(sortField.Field)
+		sortField.Direction = strings.ToLower(utils.RemoveStringSpaces(sortField.Direction))
+		if !utils.IsValidString(sortField.Direction) {
+			sortField.Direction = "asc"
+		}
+
+		if !sortableFields[sortField.Field] {
+			return "", fmt.Errorf("the field %s can't be used to sort", sortField.Field)
+		}
+
+		if usedFields[sortField.Field] {
+			return "", fmt.Errorf("the field %s is repeated in the sort", sortField.Field)
+		}
+		usedFields[sortField.Field] = true
+
+		if sortField.Direction != "asc" && sortField.Direction != "desc" {
+			return "", fmt.Errorf("the sort direction %s is not valid", sortField.Direction)
+		}
+
+		if sortField.Direction != sortFields[0].Direction {
+			return "", fmt.Errorf("all the sort fields must have the same direction")
+		}
+
+		sortClause += `{"` + sortField.Field + `":"` + sortField.Direction + `"},`
+	}
+
+	if !hasSortIndex(sortFields) {
+		fields := []string{}
+		for _, sortField := range sortFields {
+			fields = append(fields, sortField.Field)
+		}
+		return "", fmt.Errorf("there is no index to sort by %s", strings.Join(fields, ", "))
+	}
+
+	sortClause = strings.TrimSuffix(sortClause, ",")
+	sortClause += `]`
+	return sortClause, nil
+}
+
+func hasSortIndex(sortFields []dtos.SortField) bool {
+	for _, indexFields := range sortIndexes {
+		if len(indexFields) != len(sortFields) {
+			continue
+		}
+
+		matches := true
+		for i, indexField := range indexFields {
+			if sortFields[i].Field != indexField {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func isAnySortFieldInSelector(sortFields []dtos.SortField, selectorFields map[string]bool) bool {
+	for _, sortField := range sortFields {
+		if selectorFields[sortField.Field] {
+			return true
+		}
+	}
+	return false
+}
+
 func isTimeFilterValid(filter *dtos.TimestampFilter) (bool, error) {
 	if filter.Min.IsZero() && filter.Max.IsZero() {
 		return false, nil
@@ -137,6 +307,19 @@ func isTimeFilterValid(filter *dtos.TimestampFilter) (bool, error) {
 	return true, nil
 }
 
+func getTimeFilterField(filter *dtos.TimestampFilter) (string, error) {
+	switch utils.RemoveStringSpaces(filter.Field) {
+	case "", "declared":
+		return "timestamp", nil
+	case "recorded":
+		return "recorded_at", nil
+	case "updated":
+		return "updated_at", nil
+	default:
+		return "", fmt.Errorf("the time filter field %s is not valid", filter.Field)
+	}
+}
+
 func encodeArray(arr []string) ([]byte, error) {
 	encoded, err := json.Marshal(arr)
 	if err != nil {
@@ -146,9 +329,20 @@ func encodeArray(arr []string) ([]byte, error) {
 	return encoded, nil
 }
 
-func cleanFilter(filterDecoded *dtos.Filter) {
+// cleanFilter also normalizes the hashes with the hash_algorithm of the filter like in CreateAsset, so they match
+// the stored lowercase hex
+func cleanFilter(filterDecoded *dtos.Filter) error {
 	if filterDecoded.Hashs != nil {
 		clearAllStringFields(&filterDecoded.Hashs)
+		hashs := []string{}
+		for _, hash := range filterDecoded.Hashs {
+			lookupHashes, err := getLookupHashes(filterDecoded.HashAlgorithm, hash)
+			if err != nil {
+				return err
+			}
+			hashs = append(hashs, lookupHashes...)
+		}
+		filterDecoded.Hashs = hashs
 	}
 
 	if filterDecoded.Ids != nil {
@@ -162,6 +356,8 @@ func cleanFilter(filterDecoded *dtos.Filter) {
 	if filterDecoded.TypeForms != nil {
 		clearAllStringFields(&filterDecoded.TypeForms)
 	}
+
+	return nil
 }
 
 func clearAllStringFields(value *[]string) {
@@ -175,14 +371,15 @@ func (s *SmartContract) queryAllSetsWithPagination(
 	query string,
 	page int,
 	size int,
-) ([]*dtos.GetAllAssetsRequest, error) {
+) ([]*dtos.GetAllAssetsRequest, string, error) {
 	allAssets := []*dtos.GetAllAssetsRequest{}
 	bookmark := ""
+	pageBookmark := ""
 	for i := 0; i <= page; i++ {
 		isInCorrectPage := i == page
 		canContinue, newBookMark, err := querySinglePage(context, query, int32(size), bookmark, &allAssets, isInCorrectPage)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		if !isThereANewPage(bookmark, newBookMark) {
@@ -190,12 +387,35 @@ func (s *SmartContract) queryAllSetsWithPagination(
 		}
 		bookmark = newBookMark
 
+		if isInCorrectPage {
+			pageBookmark = newBookMark
+		}
+
 		if !canContinue {
 			break
 		}
 	}
 
-	return allAssets, nil
+	return allAssets, pageBookmark, nil
+}
+
+func countAssets(context contractapi.TransactionContextInterface, query string) (int, error) {
+	iterator, err := context.GetStub().GetQueryResult(query)
+	if err != nil {
+		return 0, fmt.Errorf("error counting the assets %s", err)
+	}
+	defer iterator.Close()
+
+	total := 0
+	for iterator.HasNext() {
+		_, err := iterator.Next()
+		if err != nil {
+			return 0, fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+		total++
+	}
+
+	return total, nil
 }
 
 func isThereANewPage(currentBookmark string, newBookmark string) bool {
@@ -224,21 +444,30 @@ func querySinglePage(
 		return true, responseMetadata.Bookmark, nil
 	}
 
+	err = appendAssetsFromIterator(iterator, getAllAssetRequestDto)
+	if err != nil {
+		return false, bookmark, err
+	}
+
+	return responseMetadata.Bookmark != "", responseMetadata.Bookmark, nil
+}
+
+func appendAssetsFromIterator(iterator shim.StateQueryIteratorInterface, getAllAssetRequestDto *[]*dtos.GetAllAssetsRequest) error {
 	for iterator.HasNext() {
 		queryResponse, err := iterator.Next()
 		if err != nil {
-			return false, bookmark, fmt.Errorf("error getting an item from the iterator %s", err)
+			return fmt.Errorf("error getting an item from the iterator %s", err)
 		}
 
 		asset := &dtos.GetAllAssetsRequest{}
 		err = json.Unmarshal(queryResponse.Value, asset)
 		if err != nil {
-			return false, bookmark, fmt.Errorf("error decoding value from the ledger %s", err)
+			return fmt.Errorf("error decoding value from the ledger %s", err)
 		}
 		*getAllAssetRequestDto = append(*getAllAssetRequestDto, asset)
 	}
 
-	return responseMetadata.Bookmark != "", responseMetadata.Bookmark, nil
+	return nil
 }
 
 func validateDataGetAllAssets(pageString string, sizeString string) (int, int, error) {
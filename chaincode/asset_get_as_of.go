@@ -0,0 +1,97 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"strings"
+	"time"
+)
+
+const (
+	existingAsOfStatus   = "existing"
+	notCreatedAsOfStatus = "not_created"
+	deletedAsOfStatus    = "deleted"
+)
+
+func (s *SmartContract) GetAssetAsOf(context contractapi.TransactionContextInterface, id string, asOf string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	cleanId, asOfTime, err := validateGetAssetAsOfData(id, asOf)
+	if err != nil {
+		return "", err
+	}
+
+	version, hasHistory, err := findVersionAsOf(context, cleanId, asOfTime)
+	if err != nil {
+		return "", err
+	}
+
+	if !hasHistory {
+		return "", fmt.Errorf("the asset doesn't exist")
+	}
+
+	response := &dtos.AssetAsOfResponse{
+		Status:  existingAsOfStatus,
+		AsOf:    asOfTime,
+		Version: version,
+	}
+
+	if version == nil {
+		response.Status = notCreatedAsOfStatus
+	} else if version.IsDelete {
+		response.Status = deletedAsOfStatus
+	}
+
+	responseEncoded, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the final result %s", err)
+	}
+
+	return string(responseEncoded), nil
+}
+
+func validateGetAssetAsOfData(id string, asOf string) (string, time.Time, error) {
+	cleanId := utils.RemoveStringSpaces(id)
+	if !utils.IsValidString(cleanId) {
+		return "", time.Time{}, fmt.Errorf("the id is not valid")
+	}
+
+	asOfTime, err := time.Parse(time.RFC3339, strings.TrimSpace(asOf))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("the time %s is not valid", asOf)
+	}
+
+	return cleanId, asOfTime.UTC(), nil
+}
+
+// findVersionAsOf stops at the first modification not after asOf, fabric returns the history newest first
+func findVersionAsOf(context contractapi.TransactionContextInterface, cleanId string, asOf time.Time) (*dtos.AssetHistoryEntry, bool, error) {
+	hasHistory := false
+	var version *dtos.AssetHistoryEntry
+	err := walkHistory(context, cleanId, func(modification *queryresult.KeyModification) (bool, error) {
+		hasHistory = true
+
+		if modification.Timestamp.AsTime().After(asOf) {
+			return true, nil
+		}
+
+		entry, err := decodeHistoryEntry(modification)
+		if err != nil {
+			return false, err
+		}
+		version = entry
+		return false, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return version, hasHistory, nil
+}
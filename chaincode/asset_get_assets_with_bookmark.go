@@ -0,0 +1,74 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetAssetsWithBookmark returns the page that follows the given bookmark, an empty bookmark starts from the first page
+func (s *SmartContract) GetAssetsWithBookmark(
+	context contractapi.TransactionContextInterface,
+	bookmark string,
+	sizeString string,
+	filter string,
+) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	size, err := utils.ValidateSize(sizeString)
+	if err != nil {
+		return "", err
+	}
+
+	filterDecoded, err := decodeFilter(filter)
+	if err != nil {
+		return "", err
+	}
+
+	query, err := createQuery(filterDecoded)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := queryPageFromBookmark(context, query, int32(size), bookmark)
+	if err != nil {
+		return "", err
+	}
+
+	encodedResponse, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the final result %s", err)
+	}
+
+	return string(encodedResponse), nil
+}
+
+func queryPageFromBookmark(
+	context contractapi.TransactionContextInterface,
+	query string,
+	size int32,
+	bookmark string,
+) (*dtos.GetAssetsWithBookmarkResponse, error) {
+	iterator, responseMetadata, err := context.GetStub().GetQueryResultWithPagination(query, size, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the ledger %s", err)
+	}
+	defer iterator.Close()
+
+	assets := []*dtos.GetAllAssetsRequest{}
+	err = appendAssetsFromIterator(iterator, &assets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.GetAssetsWithBookmarkResponse{
+		Items:               assets,
+		Bookmark:            responseMetadata.Bookmark,
+		FetchedRecordsCount: responseMetadata.FetchedRecordsCount,
+	}, nil
+}
@@ -9,6 +9,11 @@ import (
 )
 
 func (s *SmartContract) GetAssetById(context contractapi.TransactionContextInterface, id string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
 	clearId, err := s.validateGetAssetByIdData(context, id)
 	if err != nil {
 		return "", err
@@ -0,0 +1,129 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"form-chaincode/utils"
+	"golang.org/x/crypto/blake2b"
+	"regexp"
+	"strings"
+)
+
+const defaultHashAlgorithm = sha256Algorithm
+
+var hexDigestPattern = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+type hashAlgorithm struct {
+	size   int
+	digest func(document []byte) []byte
+}
+
+var hashAlgorithms = map[string]hashAlgorithm{
+	"sha256": {size: sha256.Size, digest: func(document []byte) []byte {
+		digest := sha256.Sum256(document)
+		return digest[:]
+	}},
+	"sha384": {size: sha512.Size384, digest: func(document []byte) []byte {
+		digest := sha512.Sum384(document)
+		return digest[:]
+	}},
+	"sha3-256": {size: 32, digest: func(document []byte) []byte {
+		digest := sha3.Sum256(document)
+		return digest[:]
+	}},
+	"blake2b-256": {size: blake2b.Size256, digest: func(document []byte) []byte {
+		digest := blake2b.Sum256(document)
+		return digest[:]
+	}},
+	"blake2b-512": {size: blake2b.Size, digest: func(document []byte) []byte {
+		digest := blake2b.Sum512(document)
+		return digest[:]
+	}},
+}
+
+// hashAlgorithmAliases maps the accepted spellings to the stored name of the algorithm
+var hashAlgorithmAliases = map[string]string{
+	"sha256":      "sha256",
+	"sha-256":     "sha256",
+	"sha384":      "sha384",
+	"sha-384":     "sha384",
+	"sha3-256":    "sha3-256",
+	"blake2b":     "blake2b-512",
+	"blake2b-512": "blake2b-512",
+	"blake2b-256": "blake2b-256",
+}
+
+// getHashAlgorithm returns the stored name of the algorithm, an empty name is sha256
+func getHashAlgorithm(name string) (string, error) {
+	cleanName := strings.ToLower(strings.ReplaceAll(utils.RemoveStringSpaces(name), "_", "-"))
+	if !utils.IsValidString(cleanName) {
+		return defaultHashAlgorithm, nil
+	}
+
+	algorithm, found := hashAlgorithmAliases[cleanName]
+	if !found {
+		return "", fmt.Errorf("the hash algorithm %s is not supported", name)
+	}
+
+	return algorithm, nil
+}
+
+// normalizeHash checks that the hash is a digest of the algorithm encoded in hex or base64 and returns it in
+// lowercase hex, which is how hashes are stored
+func normalizeHash(algorithmName string, hash string) (string, string, error) {
+	algorithm, err := getHashAlgorithm(algorithmName)
+	if err != nil {
+		return "", "", err
+	}
+
+	digest, err := decodeDigest(hash, hashAlgorithms[algorithm].size)
+	if err != nil {
+		return "", "", fmt.Errorf("the hash is not a valid %s digest", algorithm)
+	}
+
+	return algorithm, hex.EncodeToString(digest), nil
+}
+
+// decodeDigest only reads hex when every character is hex, since a sha256 hex digest is also the base64 of
+// 48 bytes, a base64 digest made only of hex characters is too unlikely to matter
+func decodeDigest(encodedDigest string, size int) ([]byte, error) {
+	if hexDigestPattern.MatchString(encodedDigest) {
+		if len(encodedDigest) != hex.EncodedLen(size) {
+			return nil, fmt.Errorf("the hex digest has %d characters instead of %d", len(encodedDigest), hex.EncodedLen(size))
+		}
+		return hex.DecodeString(encodedDigest)
+	}
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		digest, err := encoding.DecodeString(encodedDigest)
+		if err == nil && len(digest) == size {
+			return digest, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the digest is not encoded in hex or base64")
+}
+
+// getLookupHashes returns the hashes to look up for the hash of a filter, the normalized digest and the cleaned
+// value when it differs, so the legacy assets stored in uppercase hex or with a hash that is not a digest match
+func getLookupHashes(algorithmName string, hash string) ([]string, error) {
+	_, err := getHashAlgorithm(algorithmName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, normalizedHash, err := normalizeHash(algorithmName, hash)
+	if err != nil || normalizedHash == hash {
+		return []string{hash}, nil
+	}
+
+	return []string{normalizedHash, hash}, nil
+}
+
+func digestDocument(algorithm string, document []byte) string {
+	return hex.EncodeToString(hashAlgorithms[algorithm].digest(document))
+}
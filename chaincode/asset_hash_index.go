@@ -0,0 +1,229 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+// hashIndexName is the object type of the composite keys from a hash to the ids of the assets holding it
+const hashIndexName = "hash~id"
+
+const (
+	rejectDuplicateHashPolicy = "reject"
+	warnDuplicateHashPolicy   = "warn"
+	allowDuplicateHashPolicy  = "allow"
+)
+
+// hashIndexValue is stored in the index keys since fabric handles an empty value as a delete
+var hashIndexValue = []byte{0x00}
+
+// FindAssetsByHash returns the assets holding the hash, soft deleted assets included, the hash is normalized
+// with the given algorithm like in CreateAsset and also looked up as given for the legacy assets
+func (s *SmartContract) FindAssetsByHash(context contractapi.TransactionContextInterface, hash string, hashAlgorithm string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	lookupHashes, err := getLookupHashes(hashAlgorithm, utils.RemoveStringSpaces(hash))
+	if err != nil {
+		return "", err
+	}
+
+	assets := []*dtos.AssetRequest{}
+	foundIds := map[string]bool{}
+	for _, lookupHash := range lookupHashes {
+		ids, err := getIdsByHash(context, lookupHash)
+		if err != nil {
+			return "", err
+		}
+
+		for _, id := range ids {
+			if foundIds[id] {
+				continue
+			}
+			foundIds[id] = true
+
+			asset, err := s.getDataFromLedgerById(context, id)
+			if err != nil {
+				return "", err
+			}
+			assets = append(assets, asset)
+		}
+	}
+
+	assetsEncoded, err := json.Marshal(assets)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the assets %s", err)
+	}
+
+	return string(assetsEncoded), nil
+}
+
+// RebuildHashIndex indexes the assets stored before the hash index existed, it walks the assets from startKey
+// and returns the key to start the next call with, which is empty once every asset is indexed
+func (s *SmartContract) RebuildHashIndex(context contractapi.TransactionContextInterface, startKey string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	iterator, err := context.GetStub().GetStateByRange(startKey, "")
+	if err != nil {
+		return "", fmt.Errorf("error querying the ledger %s", err)
+	}
+	defer iterator.Close()
+
+	indexed := 0
+	for iterator.HasNext() {
+		queryResponse, err := iterator.Next()
+		if err != nil {
+			return "", fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+
+		if indexed == config.MaxBatchSize {
+			return queryResponse.Key, nil
+		}
+
+		asset := &dtos.AssetRequest{}
+		err = json.Unmarshal(queryResponse.Value, asset)
+		if err != nil || !isAssetDocument(queryResponse.Key, asset) {
+			continue
+		}
+
+		err = putHashIndex(context, asset.Hash, queryResponse.Key)
+		if err != nil {
+			return "", err
+		}
+		indexed++
+	}
+
+	return "", nil
+}
+
+// isAssetDocument also recognises the assets written before doc_type existed, they are stored under their own id
+func isAssetDocument(key string, asset *dtos.AssetRequest) bool {
+	if asset.DocType == assetDocType {
+		return true
+	}
+
+	return asset.DocType == "" && asset.Id != "" && asset.Id == key
+}
+
+// checkDuplicateHash applies the duplicate hash policy, it returns the ids of the other assets holding the hash
+// when the policy is warn, soft deleted assets are not duplicates
+func (s *SmartContract) checkDuplicateHash(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, hash string, id string) ([]string, error) {
+	if config.DuplicateHashPolicy == allowDuplicateHashPolicy {
+		return nil, nil
+	}
+
+	ids, err := getIdsByHash(context, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateIds := []string{}
+	for _, otherId := range ids {
+		if otherId == id {
+			continue
+		}
+
+		otherAsset, err := s.getDataFromLedgerById(context, otherId)
+		if err != nil {
+			return nil, err
+		}
+
+		if !otherAsset.Deleted {
+			duplicateIds = append(duplicateIds, otherId)
+		}
+	}
+
+	if len(duplicateIds) == 0 {
+		return nil, nil
+	}
+
+	if config.DuplicateHashPolicy == rejectDuplicateHashPolicy {
+		return nil, fmt.Errorf("the hash is already used by %s", strings.Join(duplicateIds, ", "))
+	}
+
+	return duplicateIds, nil
+}
+
+func getIdsByHash(context contractapi.TransactionContextInterface, hash string) ([]string, error) {
+	iterator, err := context.GetStub().GetStateByPartialCompositeKey(hashIndexName, []string{hash})
+	if err != nil {
+		return nil, fmt.Errorf("error querying the hash index %s", err)
+	}
+	defer iterator.Close()
+
+	ids := []string{}
+	for iterator.HasNext() {
+		queryResponse, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+
+		_, attributes, err := context.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil || len(attributes) != 2 {
+			return nil, fmt.Errorf("the hash index key %s is not valid", queryResponse.Key)
+		}
+		ids = append(ids, attributes[1])
+	}
+
+	return ids, nil
+}
+
+func putHashIndex(context contractapi.TransactionContextInterface, hash string, id string) error {
+	indexKey, err := getHashIndexKey(context, hash, id)
+	if err != nil {
+		return err
+	}
+
+	err = context.GetStub().PutState(indexKey, hashIndexValue)
+	if err != nil {
+		return fmt.Errorf("error saving the hash index %s", err)
+	}
+
+	return nil
+}
+
+func deleteHashIndex(context contractapi.TransactionContextInterface, hash string, id string) error {
+	indexKey, err := getHashIndexKey(context, hash, id)
+	if err != nil {
+		return err
+	}
+
+	err = context.GetStub().DelState(indexKey)
+	if err != nil {
+		return fmt.Errorf("error deleting the hash index %s", err)
+	}
+
+	return nil
+}
+
+func moveHashIndex(context contractapi.TransactionContextInterface, previousHash string, hash string, id string) error {
+	err := deleteHashIndex(context, previousHash, id)
+	if err != nil {
+		return err
+	}
+
+	return putHashIndex(context, hash, id)
+}
+
+func getHashIndexKey(context contractapi.TransactionContextInterface, hash string, id string) (string, error) {
+	indexKey, err := context.GetStub().CreateCompositeKey(hashIndexName, []string{hash, id})
+	if err != nil {
+		return "", fmt.Errorf("error creating the hash index key %s", err)
+	}
+
+	return indexKey, nil
+}
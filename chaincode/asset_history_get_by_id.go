@@ -3,45 +3,123 @@ package chaincode
 import (
 	"encoding/json"
 	"fmt"
+	"form-chaincode/dtos"
 	"form-chaincode/utils"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 )
 
 func (s *SmartContract) GetHistoryAssetById(context contractapi.TransactionContextInterface, id string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
 	cleanId := utils.RemoveStringSpaces(id)
-	if !s.exists(context, cleanId) {
-		return "", fmt.Errorf("the asset doesn't exist")
+	if !utils.IsValidString(cleanId) {
+		return "", fmt.Errorf("the id is not valid")
 	}
 
+	// the history is kept after the key is deleted, so the current state is not checked
 	assetHistory, err := GetHistoryFromCleanKey(context, cleanId)
 	if err != nil {
 		return "", err
 	}
 
-	return MarshalHistoryAndReturnStringValue(assetHistory)
+	if len(assetHistory) == 0 {
+		return "", fmt.Errorf("the asset doesn't exist")
+	}
+
+	decodedHistory, err := decodeHistory(assetHistory)
+	if err != nil {
+		return "", err
+	}
+
+	return MarshalHistoryAndReturnStringValue(decodedHistory)
 }
 
 func GetHistoryFromCleanKey(context contractapi.TransactionContextInterface, cleanId string) ([]*queryresult.KeyModification, error) {
+	assetHistory := []*queryresult.KeyModification{}
+	err := walkHistory(context, cleanId, func(modification *queryresult.KeyModification) (bool, error) {
+		assetHistory = append(assetHistory, modification)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assetHistory, nil
+}
+
+// walkHistory calls visit with every modification of the key, newest first like fabric returns them, until
+// visit returns false or an error
+func walkHistory(
+	context contractapi.TransactionContextInterface,
+	cleanId string,
+	visit func(modification *queryresult.KeyModification) (bool, error),
+) error {
 	iterator, err := context.GetStub().GetHistoryForKey(cleanId)
 	if err != nil {
-		return nil, fmt.Errorf("something went wrong getting the item history: %s", err.Error())
+		return fmt.Errorf("something went wrong getting the item history: %s", err.Error())
 	}
 	defer iterator.Close()
 
-	assetHistory := []*queryresult.KeyModification{}
 	for iterator.HasNext() {
-		asset, err := iterator.Next()
+		modification, err := iterator.Next()
 		if err != nil {
-			return nil, fmt.Errorf("something went wring retriving the next item from the history: %s", err.Error())
+			return fmt.Errorf("something went wrong retrieving the next item from the history: %s", err.Error())
+		}
+
+		next, err := visit(modification)
+		if err != nil {
+			return err
+		}
+
+		if !next {
+			return nil
 		}
-		assetHistory = append(assetHistory, asset)
 	}
 
-	return assetHistory, nil
+	return nil
+}
+
+func decodeHistory(assetHistory []*queryresult.KeyModification) ([]*dtos.AssetHistoryEntry, error) {
+	decodedHistory := []*dtos.AssetHistoryEntry{}
+	for _, modification := range assetHistory {
+		entry, err := decodeHistoryEntry(modification)
+		if err != nil {
+			return nil, err
+		}
+		decodedHistory = append(decodedHistory, entry)
+	}
+
+	return decodedHistory, nil
+}
+
+// decodeHistoryEntry flags the entries that wrote a tombstone so they read the same as a hard delete
+func decodeHistoryEntry(modification *queryresult.KeyModification) (*dtos.AssetHistoryEntry, error) {
+	entry := &dtos.AssetHistoryEntry{
+		TxId:      modification.TxId,
+		Timestamp: modification.Timestamp.AsTime().UTC(),
+		IsDelete:  modification.IsDelete,
+	}
+
+	if modification.IsDelete {
+		return entry, nil
+	}
+
+	asset := &dtos.AssetRequest{}
+	err := json.Unmarshal(modification.Value, asset)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the history entry %s %s", modification.TxId, err)
+	}
+
+	entry.IsDelete = asset.Deleted
+	entry.Asset = asset
+	return entry, nil
 }
 
-func MarshalHistoryAndReturnStringValue(assetHistory []*queryresult.KeyModification) (string, error) {
+func MarshalHistoryAndReturnStringValue(assetHistory []*dtos.AssetHistoryEntry) (string, error) {
 	assetHistoryEncoded, err := json.Marshal(assetHistory)
 	if err != nil {
 		return "", fmt.Errorf("something went wrong encoding the final result %s", err.Error())
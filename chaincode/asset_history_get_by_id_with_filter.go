@@ -0,0 +1,114 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+)
+
+func (s *SmartContract) GetHistoryAssetByIdWithFilter(
+	context contractapi.TransactionContextInterface,
+	id string,
+	offsetString string,
+	limitString string,
+	filter string,
+) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	cleanId, offset, limit, historyFilter, err := validateHistoryWithFilterData(id, offsetString, limitString, filter)
+	if err != nil {
+		return "", err
+	}
+
+	page, err := queryHistoryPage(context, cleanId, offset, limit, historyFilter)
+	if err != nil {
+		return "", err
+	}
+
+	pageEncoded, err := json.Marshal(page)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the final result %s", err)
+	}
+
+	return string(pageEncoded), nil
+}
+
+func validateHistoryWithFilterData(id string, offsetString string, limitString string, filter string) (string, int, int, *dtos.HistoryFilter, error) {
+	cleanId := utils.RemoveStringSpaces(id)
+	if !utils.IsValidString(cleanId) {
+		return "", 0, 0, nil, fmt.Errorf("the id is not valid")
+	}
+
+	offset, limit, err := utils.ValidateOffsetAndLimit(offsetString, limitString)
+	if err != nil {
+		return "", 0, 0, nil, err
+	}
+
+	historyFilter := &dtos.HistoryFilter{}
+	err = json.Unmarshal([]byte(filter), historyFilter)
+	if err != nil {
+		return "", 0, 0, nil, fmt.Errorf("error decoding filter %s", err)
+	}
+
+	if !historyFilter.Min.IsZero() && !historyFilter.Max.IsZero() && historyFilter.Min.After(historyFilter.Max) {
+		return "", 0, 0, nil, fmt.Errorf("minimum interval should not be after the maximum")
+	}
+
+	return cleanId, offset, limit, historyFilter, nil
+}
+
+// queryHistoryPage decodes only the modifications of the page, fabric returns the history newest first
+// so the iteration stops as soon as a modification is older than the window or the page is full
+func queryHistoryPage(
+	context contractapi.TransactionContextInterface,
+	cleanId string,
+	offset int,
+	limit int,
+	historyFilter *dtos.HistoryFilter,
+) (*dtos.AssetHistoryPage, error) {
+	page := &dtos.AssetHistoryPage{
+		Items:  []*dtos.AssetHistoryEntry{},
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	matched := 0
+	err := walkHistory(context, cleanId, func(modification *queryresult.KeyModification) (bool, error) {
+		modificationTime := modification.Timestamp.AsTime()
+		if !historyFilter.Max.IsZero() && modificationTime.After(historyFilter.Max) {
+			return true, nil
+		}
+
+		if !historyFilter.Min.IsZero() && modificationTime.Before(historyFilter.Min) {
+			return false, nil
+		}
+
+		matched++
+		if matched <= offset {
+			return true, nil
+		}
+
+		if len(page.Items) == limit {
+			page.HasMore = true
+			return false, nil
+		}
+
+		entry, err := decodeHistoryEntry(modification)
+		if err != nil {
+			return false, err
+		}
+		page.Items = append(page.Items, entry)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
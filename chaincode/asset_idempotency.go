@@ -0,0 +1,118 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	idempotencyDocType    = "idempotency"
+	idempotencyObjectType = "idempotency"
+)
+
+// replayCreateAsset returns the asset created by a previous request with the same idempotency key,
+// it is nil when the request has no key or the key was not used yet
+func (s *SmartContract) replayCreateAsset(context contractapi.TransactionContextInterface, request *dtos.PostAssetRequest) (*dtos.AssetRequest, error) {
+	if !utils.IsValidString(request.IdempotencyKey) {
+		return nil, nil
+	}
+
+	recordKey, err := getIdempotencyRecordKey(context, request.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedRecord, err := context.GetStub().GetState(recordKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the idempotency record %s", err)
+	}
+
+	if len(encodedRecord) == 0 {
+		return nil, nil
+	}
+
+	record := &dtos.IdempotencyRecord{}
+	err = json.Unmarshal(encodedRecord, record)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the idempotency record %s", err)
+	}
+
+	requestHash, err := hashCreateRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, fmt.Errorf("the idempotency key %s was already used with a different request", request.IdempotencyKey)
+	}
+
+	return record.Asset, nil
+}
+
+func (s *SmartContract) saveIdempotencyRecord(context contractapi.TransactionContextInterface, request *dtos.PostAssetRequest, asset *dtos.AssetRequest) error {
+	if !utils.IsValidString(request.IdempotencyKey) {
+		return nil
+	}
+
+	recordKey, err := getIdempotencyRecordKey(context, request.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	requestHash, err := hashCreateRequest(request)
+	if err != nil {
+		return err
+	}
+
+	record := &dtos.IdempotencyRecord{
+		DocType:     idempotencyDocType,
+		Key:         request.IdempotencyKey,
+		RequestHash: requestHash,
+		Asset:       asset,
+	}
+
+	encodedRecord, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding the idempotency record %s", err)
+	}
+
+	err = context.GetStub().PutState(recordKey, encodedRecord)
+	if err != nil {
+		return fmt.Errorf("error saving the idempotency record %s", err)
+	}
+
+	return nil
+}
+
+// getIdempotencyRecordKey scopes the key to the caller msp so an organization can't replay the requests of another one
+func getIdempotencyRecordKey(context contractapi.TransactionContextInterface, idempotencyKey string) (string, error) {
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return "", err
+	}
+
+	recordKey, err := context.GetStub().CreateCompositeKey(idempotencyObjectType, []string{caller.MspId, idempotencyKey})
+	if err != nil {
+		return "", fmt.Errorf("error creating the idempotency key %s", err)
+	}
+
+	return recordKey, nil
+}
+
+func hashCreateRequest(request *dtos.PostAssetRequest) (string, error) {
+	requestWithoutKey := *request
+	requestWithoutKey.IdempotencyKey = ""
+
+	encodedRequest, err := json.Marshal(&requestWithoutKey)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the request %s", err)
+	}
+
+	requestHash := sha256.Sum256(encodedRequest)
+	return hex.EncodeToString(requestHash[:]), nil
+}
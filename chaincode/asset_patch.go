@@ -9,31 +9,85 @@ import (
 )
 
 func (s *SmartContract) PatchAsset(context contractapi.TransactionContextInterface, encodedData string, id string) (string, error) {
+	callerRole, err := s.authorize(context, editorRoles...)
+	if err != nil {
+		return "", err
+	}
+
 	clearId, clearRequest, err := s.validatePatchData(context, encodedData, id)
 	if err != nil {
 		return "", err
 	}
 
-	asset, err := s.patchAsset(context, clearRequest, clearId)
+	err = s.ensureOwnerOrAdmin(context, callerRole, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getChaincodeConfig(context)
 	if err != nil {
 		return "", err
 	}
 
-	assetEncoded, err := json.Marshal(asset)
+	patch, err := s.patchAsset(context, config, clearRequest, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	err = emitAssetEventWithDuplicates(context, formPatchedEvent, clearId, patch.changedFields, patch.duplicateIds)
+	if err != nil {
+		return "", err
+	}
+
+	assetEncoded, err := json.Marshal(patch.asset)
 	return string(assetEncoded), nil
 }
 
-func (s *SmartContract) patchAsset(context contractapi.TransactionContextInterface, request *dtos.PutAssetRequest, clearId string) (*dtos.AssetRequest, error) {
-	asset, err := s.GetAssetById(context, clearId)
+// patchedAsset is a patch with every check done, so nothing is written for the patches that fail
+type patchedAsset struct {
+	id            string
+	asset         *dtos.AssetRequest
+	previousHash  string
+	encodedAsset  []byte
+	changedFields []string
+	duplicateIds  []string
+}
+
+func (s *SmartContract) patchAsset(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, request *dtos.PutAssetRequest, clearId string) (*patchedAsset, error) {
+	patch, err := s.preparePatch(context, config, request, clearId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writePatch(context, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+func (s *SmartContract) preparePatch(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, request *dtos.PutAssetRequest, clearId string) (*patchedAsset, error) {
+	assetDecoded, err := s.getDataFromLedgerById(context, clearId)
 	if err != nil {
 		return nil, err
 	}
 
-	assetDecoded := &dtos.AssetRequest{}
-	err = json.Unmarshal([]byte(asset), assetDecoded)
+	if assetDecoded.Deleted {
+		return nil, fmt.Errorf("the asset is deleted")
+	}
 
-	if utils.IsValidString(request.Hash) {
-		assetDecoded.Hash = request.Hash
+	err = checkExpectedVersion(assetDecoded, request.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	previousAsset := *assetDecoded
+
+	if utils.IsValidString(request.Hash) || utils.IsValidString(request.HashAlgorithm) {
+		err = patchHash(assetDecoded, request)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if utils.IsValidString(request.TypeForm) {
@@ -44,7 +98,11 @@ func (s *SmartContract) patchAsset(context contractapi.TransactionContextInterfa
 		assetDecoded.Timestamp = request.Timestamp
 	}
 
-	if utils.IsValidString(request.InsertionType) {
+	if utils.IsValidString(request.InsertionType) && request.InsertionType != assetDecoded.InsertionType {
+		err = validateInsertionType(context, config, request.InsertionType)
+		if err != nil {
+			return nil, err
+		}
 		assetDecoded.InsertionType = request.InsertionType
 	}
 
@@ -52,17 +110,95 @@ func (s *SmartContract) patchAsset(context contractapi.TransactionContextInterfa
 		assetDecoded.Description = request.Description
 	}
 
+	if len(request.Fields) != 0 {
+		assetDecoded.Fields = mergeFields(assetDecoded.Fields, request.Fields)
+		err = validateFieldsSize(config, assetDecoded.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var duplicateIds []string
+	if assetDecoded.Hash != previousAsset.Hash {
+		duplicateIds, err = s.checkDuplicateHash(context, config, assetDecoded.Hash, clearId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the assets stored before their form type was registered stay patchable as long as the patch keeps
+	// their type_form and fields
+	if assetDecoded.TypeForm != previousAsset.TypeForm || len(request.Fields) != 0 {
+		err = validateAssetFormType(context, config, assetDecoded.TypeForm, assetDecoded.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = stampAssetUpdate(context, assetDecoded)
+	if err != nil {
+		return nil, err
+	}
+
 	encodedData, err := json.Marshal(assetDecoded)
 	if err != nil {
 		return nil, fmt.Errorf("error encoding asset after changing values %s", err)
 	}
 
-	err = context.GetStub().PutState(clearId, encodedData)
+	changedFields, err := getChangedFields(&previousAsset, assetDecoded)
 	if err != nil {
-		return nil, fmt.Errorf("error updating ledger %s", err)
+		return nil, err
 	}
 
-	return assetDecoded, nil
+	return &patchedAsset{
+		id:            clearId,
+		asset:         assetDecoded,
+		previousHash:  previousAsset.Hash,
+		encodedAsset:  encodedData,
+		changedFields: changedFields,
+		duplicateIds:  duplicateIds,
+	}, nil
+}
+
+// writePatch stores the patched asset and moves its hash index, a batch fails as a whole on its errors since
+// the asset could already be written
+func writePatch(context contractapi.TransactionContextInterface, patch *patchedAsset) error {
+	err := context.GetStub().PutState(patch.id, patch.encodedAsset)
+	if err != nil {
+		return fmt.Errorf("error updating ledger %s", err)
+	}
+
+	if patch.asset.Hash != patch.previousHash {
+		err = moveHashIndex(context, patch.previousHash, patch.asset.Hash, patch.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchHash validates the new hash against the new or the stored algorithm, changing only the algorithm
+// validates the stored hash against it
+func patchHash(asset *dtos.AssetRequest, request *dtos.PutAssetRequest) error {
+	hash := asset.Hash
+	if utils.IsValidString(request.Hash) {
+		hash = request.Hash
+	}
+
+	algorithm := asset.HashAlgorithm
+	if utils.IsValidString(request.HashAlgorithm) {
+		algorithm = request.HashAlgorithm
+	}
+
+	normalizedAlgorithm, normalizedHash, err := normalizeHash(algorithm, hash)
+	if err != nil {
+		return err
+	}
+
+	asset.HashAlgorithm = normalizedAlgorithm
+	asset.Hash = normalizedHash
+	return nil
 }
 
 func (s *SmartContract) validatePatchData(context contractapi.TransactionContextInterface, encodedData string, id string) (string, *dtos.PutAssetRequest, error) {
@@ -76,29 +212,53 @@ func (s *SmartContract) validatePatchData(context contractapi.TransactionContext
 		return "", nil, fmt.Errorf("it doesn't exist")
 	}
 
+	request, err := decodePatchRequest(encodedData)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clearId, request, nil
+}
+
+func decodePatchRequest(encodedData string) (*dtos.PutAssetRequest, error) {
 	encodedDataBytes := []byte(encodedData)
 
 	request := &dtos.PutAssetRequest{}
 	err := json.Unmarshal(encodedDataBytes, request)
 	if err != nil {
-		return "", nil, fmt.Errorf("decoding the object %s", err)
+		return nil, fmt.Errorf("decoding the object %s", err)
 	}
 
 	if removeSpacesAndCheckIfOnePropertyToChange(request) {
-		return "", nil, fmt.Errorf("nothing to change in the request")
+		return nil, fmt.Errorf("nothing to change in the request")
 	}
 
-	return clearId, request, nil
+	if request.ExpectedVersion != nil {
+		err = validateExpectedVersion(*request.ExpectedVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = validateFieldKeys(request.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
 }
 
 func removeSpacesAndCheckIfOnePropertyToChange(request *dtos.PutAssetRequest) bool {
 	request.TypeForm = utils.RemoveStringSpaces(request.TypeForm)
 	request.Hash = utils.RemoveStringSpaces(request.Hash)
+	request.HashAlgorithm = utils.RemoveStringSpaces(request.HashAlgorithm)
 	request.InsertionType = utils.RemoveStringSpaces(request.InsertionType)
 	request.Description = utils.RemoveStringSpaces(request.Description)
 
 	return !utils.IsValidString(request.TypeForm) &&
 		!utils.IsValidString(request.Hash) &&
+		!utils.IsValidString(request.HashAlgorithm) &&
 		!utils.IsValidString(request.InsertionType) &&
-		!utils.IsValidString(request.Description)
+		!utils.IsValidString(request.Description) &&
+		len(request.Fields) == 0
 }
@@ -0,0 +1,117 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	privateDetailsDocType      = "form_private_details"
+	privateDetailsTransientKey = "private_details"
+)
+
+// GetAssetPrivateDetails returns the content kept in the private data collection of the asset, it is only
+// answered by the peers of the caller organization
+func (s *SmartContract) GetAssetPrivateDetails(context contractapi.TransactionContextInterface, id string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	clearId, err := s.validateGetAssetByIdData(context, id)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	if !utils.IsValidString(asset.PrivateCollection) {
+		return "", fmt.Errorf("the asset has no private details")
+	}
+
+	err = ensureCallerFromPeerOrg(context)
+	if err != nil {
+		return "", err
+	}
+
+	encodedDetails, err := context.GetStub().GetPrivateData(asset.PrivateCollection, clearId)
+	if err != nil {
+		return "", fmt.Errorf("error reading the private details %s", err)
+	}
+
+	if len(encodedDetails) == 0 {
+		return "", fmt.Errorf("the private details are not available on this peer")
+	}
+
+	return string(encodedDetails), nil
+}
+
+// readPrivateDetails returns the private details sent in the transient map and the collection to store them,
+// the details are nil when the transaction has none and rejected when no collection is configured
+func readPrivateDetails(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig) (*dtos.AssetPrivateDetails, string, error) {
+	transientMap, err := context.GetStub().GetTransient()
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading the transient map %s", err)
+	}
+
+	encodedContent, found := transientMap[privateDetailsTransientKey]
+	if !found {
+		return nil, "", nil
+	}
+
+	collection := config.PrivateCollection
+	if !utils.IsValidString(collection) {
+		return nil, "", fmt.Errorf("the private details can't be stored since no private collection is configured")
+	}
+
+	content := map[string]interface{}{}
+	err = json.Unmarshal(encodedContent, &content)
+	if err != nil || len(content) == 0 {
+		return nil, "", fmt.Errorf("the private details are not a valid json object")
+	}
+
+	return &dtos.AssetPrivateDetails{DocType: privateDetailsDocType, Content: content}, collection, nil
+}
+
+func savePrivateDetails(context contractapi.TransactionContextInterface, collection string, id string, details *dtos.AssetPrivateDetails) error {
+	details.Id = id
+	encodedDetails, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("error encoding the private details %s", err)
+	}
+
+	err = context.GetStub().PutPrivateData(collection, id, encodedDetails)
+	if err != nil {
+		return fmt.Errorf("error saving the private details %s", err)
+	}
+
+	return nil
+}
+
+// ensureCallerFromPeerOrg checks that the caller belongs to the organization of the peer, so the private details
+// are only read through the peers of the caller organization, the writes are left to the memberOnlyWrite of the
+// collection since the endorsing peers of other organizations must accept them
+func ensureCallerFromPeerOrg(context contractapi.TransactionContextInterface) error {
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return err
+	}
+
+	peerMspId, err := shim.GetMSPID()
+	if err != nil {
+		return fmt.Errorf("error getting the peer msp id %s", err)
+	}
+
+	if caller.MspId != peerMspId {
+		return fmt.Errorf("the caller msp %s is not the peer msp %s", caller.MspId, peerMspId)
+	}
+
+	return nil
+}
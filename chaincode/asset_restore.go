@@ -0,0 +1,85 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+func (s *SmartContract) RestoreAsset(context contractapi.TransactionContextInterface, id string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	clearId, err := s.validateGetAssetByIdData(context, id)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	asset, changedFields, duplicateIds, err := s.restoreAsset(context, config, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	err = emitAssetEventWithDuplicates(context, formRestoredEvent, clearId, changedFields, duplicateIds)
+	if err != nil {
+		return "", err
+	}
+
+	assetEncoded, err := json.Marshal(asset)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the asset %s", err)
+	}
+
+	return string(assetEncoded), nil
+}
+
+// restoreAsset applies the duplicate hash policy again since another asset may have taken the hash
+// while the asset was deleted
+func (s *SmartContract) restoreAsset(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, clearId string) (*dtos.AssetRequest, []string, []string, error) {
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !asset.Deleted {
+		return nil, nil, nil, fmt.Errorf("the asset is not deleted")
+	}
+	previousAsset := *asset
+
+	duplicateIds, err := s.checkDuplicateHash(context, config, asset.Hash, clearId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	asset.Deleted = false
+	asset.Deletion = nil
+	err = stampAssetUpdate(context, asset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encodedData, err := json.Marshal(asset)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error encoding asset after restoring it %s", err)
+	}
+
+	err = context.GetStub().PutState(clearId, encodedData)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error updating ledger %s", err)
+	}
+
+	changedFields, err := getChangedFields(&previousAsset, asset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return asset, changedFields, duplicateIds, nil
+}
@@ -1,10 +1,28 @@
 package chaincode
 
 import (
+	"fmt"
+	"form-chaincode/dtos"
 	"form-chaincode/utils"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// assetDocType is the doc_type of the assets, every query selects it or a missing doc_type to skip the other documents
+const assetDocType = "form"
+
+// validateAssetId rejects the ids fabric can't store as a key and the ids that could collide with a composite key,
+// composite keys start with \x00 and use it between their attributes
+func validateAssetId(id string) error {
+	if !utf8.ValidString(id) || strings.Contains(id, "\x00") {
+		return fmt.Errorf("the id is not valid")
+	}
+
+	return nil
+}
+
 func (s *SmartContract) exists(context contractapi.TransactionContextInterface, id string) bool {
 	value, err := context.GetStub().GetState(id)
 	if utils.ValueExists(err, value) {
@@ -12,3 +30,32 @@ func (s *SmartContract) exists(context contractapi.TransactionContextInterface,
 	}
 	return false
 }
+
+// stampAssetUpdate records the transaction time and the caller as the last change of the asset
+// and increases its version
+func stampAssetUpdate(context contractapi.TransactionContextInterface, asset *dtos.AssetRequest) error {
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return err
+	}
+
+	txTime, err := getTxTime(context)
+	if err != nil {
+		return err
+	}
+
+	asset.DocType = assetDocType
+	asset.UpdatedAt = txTime
+	asset.UpdatedBy = *caller
+	asset.Version++
+	return nil
+}
+
+func getTxTime(context contractapi.TransactionContextInterface) (time.Time, error) {
+	txTimestamp, err := context.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting the transaction timestamp %s", err)
+	}
+
+	return txTimestamp.AsTime().UTC(), nil
+}
@@ -0,0 +1,98 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+func (s *SmartContract) TransferOwnership(context contractapi.TransactionContextInterface, id string, encodedOwner string) (string, error) {
+	callerRole, err := s.authorize(context, submitterRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	clearId, newOwner, err := s.validateTransferOwnershipData(context, id, encodedOwner)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.ensureOwnerOrAdmin(context, callerRole, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := s.transferOwnership(context, clearId, newOwner)
+	if err != nil {
+		return "", err
+	}
+
+	err = emitAssetEvent(context, formOwnershipTransferredEvent, clearId, []string{"owner"})
+	if err != nil {
+		return "", err
+	}
+
+	assetEncoded, err := json.Marshal(asset)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the asset %s", err)
+	}
+
+	return string(assetEncoded), nil
+}
+
+func (s *SmartContract) validateTransferOwnershipData(context contractapi.TransactionContextInterface, id string, encodedOwner string) (string, *dtos.Identity, error) {
+	clearId := utils.RemoveStringSpaces(id)
+	if !utils.IsValidString(clearId) {
+		return "", nil, fmt.Errorf("the id is not valid")
+	}
+
+	if !s.exists(context, clearId) {
+		return "", nil, fmt.Errorf("the asset doesn't exist")
+	}
+
+	newOwner := &dtos.Identity{}
+	err := json.Unmarshal([]byte(encodedOwner), newOwner)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding the new owner %s", err)
+	}
+
+	newOwner.MspId = utils.RemoveStringSpaces(newOwner.MspId)
+	newOwner.Subject = strings.TrimSpace(newOwner.Subject)
+	if !utils.IsValidString(newOwner.MspId) || !utils.IsValidString(newOwner.Subject) {
+		return "", nil, fmt.Errorf("the new owner is not valid")
+	}
+
+	return clearId, newOwner, nil
+}
+
+func (s *SmartContract) transferOwnership(context contractapi.TransactionContextInterface, clearId string, newOwner *dtos.Identity) (*dtos.AssetRequest, error) {
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Deleted {
+		return nil, fmt.Errorf("the asset is deleted")
+	}
+
+	asset.Owner = *newOwner
+	err = stampAssetUpdate(context, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedData, err := json.Marshal(asset)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding asset after changing the owner %s", err)
+	}
+
+	err = context.GetStub().PutState(clearId, encodedData)
+	if err != nil {
+		return nil, fmt.Errorf("error updating ledger %s", err)
+	}
+
+	return asset, nil
+}
@@ -0,0 +1,123 @@
+package chaincode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"strings"
+)
+
+const (
+	documentTransientKey = "document"
+	sha256Algorithm      = "sha256"
+)
+
+// VerifyDocumentHash compares the hash of the asset with the digest of the document sent in the transient map
+// or with the given digest, only one of them can be given, both use the hash algorithm of the asset
+func (s *SmartContract) VerifyDocumentHash(context contractapi.TransactionContextInterface, id string, digest string) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	clearId, err := s.validateGetAssetByIdData(context, id)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return "", err
+	}
+
+	if asset.Deleted {
+		return "", fmt.Errorf("the asset is deleted")
+	}
+
+	algorithm, err := getHashAlgorithm(asset.HashAlgorithm)
+	if err != nil {
+		return "", err
+	}
+
+	candidateHash, err := getCandidateHash(context, digest, algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	anchor, err := findHashAnchor(context, clearId, asset.Hash)
+	if err != nil {
+		return "", err
+	}
+
+	verification := &dtos.DocumentVerification{
+		Id:            clearId,
+		Match:         strings.EqualFold(candidateHash, asset.Hash),
+		Algorithm:     algorithm,
+		StoredHash:    asset.Hash,
+		CandidateHash: candidateHash,
+	}
+
+	if anchor != nil {
+		verification.AnchoredTxId = anchor.TxId
+		verification.AnchoredAt = anchor.Timestamp
+	}
+
+	verificationEncoded, err := json.Marshal(verification)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the verification %s", err)
+	}
+
+	return string(verificationEncoded), nil
+}
+
+// getCandidateHash returns the digest of the document or the given digest in lowercase hex
+func getCandidateHash(context contractapi.TransactionContextInterface, digest string, algorithm string) (string, error) {
+	transientMap, err := context.GetStub().GetTransient()
+	if err != nil {
+		return "", fmt.Errorf("error reading the transient map %s", err)
+	}
+
+	document, hasDocument := transientMap[documentTransientKey]
+	digest = utils.RemoveStringSpaces(digest)
+
+	if hasDocument == utils.IsValidString(digest) {
+		return "", fmt.Errorf("either the document or the digest is required")
+	}
+
+	if !hasDocument {
+		decodedDigest, err := decodeDigest(digest, hashAlgorithms[algorithm].size)
+		if err != nil {
+			return "", fmt.Errorf("the digest is not a valid %s digest", algorithm)
+		}
+		return hex.EncodeToString(decodedDigest), nil
+	}
+
+	return digestDocument(algorithm, document), nil
+}
+
+// findHashAnchor returns the oldest modification of the unbroken run of versions holding hash, that is the
+// transaction that anchored the current hash, fabric returns the history newest first
+func findHashAnchor(context contractapi.TransactionContextInterface, cleanId string, hash string) (*dtos.AssetHistoryEntry, error) {
+	var anchor *dtos.AssetHistoryEntry
+	err := walkHistory(context, cleanId, func(modification *queryresult.KeyModification) (bool, error) {
+		entry, err := decodeHistoryEntry(modification)
+		if err != nil {
+			return false, err
+		}
+
+		if entry.Asset == nil || entry.Asset.Hash != hash {
+			return false, nil
+		}
+		anchor = entry
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return anchor, nil
+}
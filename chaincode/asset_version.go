@@ -0,0 +1,49 @@
+package chaincode
+
+import (
+	"errors"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"strconv"
+)
+
+// ErrVersionConflict is wrapped by the writes that were sent with an expected version older than the stored one
+var ErrVersionConflict = errors.New("version conflict")
+
+func checkExpectedVersion(asset *dtos.AssetRequest, expectedVersion *int) error {
+	if expectedVersion == nil || asset.Version == *expectedVersion {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the asset is at version %d but version %d was expected", ErrVersionConflict, asset.Version, *expectedVersion)
+}
+
+// parseExpectedVersion returns nil when no version is given so the write doesn't check it
+func parseExpectedVersion(expectedVersion string) (*int, error) {
+	expectedVersion = utils.RemoveStringSpaces(expectedVersion)
+	if !utils.IsValidString(expectedVersion) {
+		return nil, nil
+	}
+
+	version, err := strconv.Atoi(expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("the expected version %s is not valid", expectedVersion)
+	}
+
+	err = validateExpectedVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// validateExpectedVersion accepts 0 since the assets written before versions existed are stored without one
+func validateExpectedVersion(expectedVersion int) error {
+	if expectedVersion < 0 {
+		return fmt.Errorf("the expected version %d is not valid", expectedVersion)
+	}
+
+	return nil
+}
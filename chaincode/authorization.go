@@ -0,0 +1,151 @@
+package chaincode
+
+import (
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+type role string
+
+const (
+	roleSubmitter role = "submitter"
+	roleEditor    role = "editor"
+	roleAuditor   role = "auditor"
+	roleAdmin     role = "admin"
+)
+
+// roleAttribute is the X.509 attribute (issued by the fabric CA) holding the caller role
+const roleAttribute = "form.role"
+
+var (
+	readerRoles    = []role{roleSubmitter, roleEditor, roleAuditor, roleAdmin}
+	submitterRoles = []role{roleSubmitter, roleEditor, roleAdmin}
+	editorRoles    = []role{roleEditor, roleAdmin}
+	adminRoles     = []role{roleAdmin}
+)
+
+// authorize returns the role of the caller, the callers of the admin msps of the config are admins whatever
+// their attribute, the others only get the attribute role when the config grants it to their msp
+func (s *SmartContract) authorize(context contractapi.TransactionContextInterface, allowedRoles ...role) (role, error) {
+	mspId, err := context.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return "", fmt.Errorf("error getting the caller msp id %s", err)
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	if isAdminMsp(config, mspId) && isAllowedRole(roleAdmin, allowedRoles) {
+		return roleAdmin, nil
+	}
+
+	callerRole, err := getCallerRole(context, config, mspId)
+	if err != nil {
+		return "", err
+	}
+
+	if !isAllowedRole(callerRole, allowedRoles) {
+		return "", fmt.Errorf("the role %s is not allowed to perform this operation", callerRole)
+	}
+
+	return callerRole, nil
+}
+
+// getCallerRole returns the attribute role of the caller, the CA of any msp can issue the attribute so it only
+// counts when the msp_roles of the config grant the role to the caller msp
+func getCallerRole(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, mspId string) (role, error) {
+	value, found, err := context.GetClientIdentity().GetAttributeValue(roleAttribute)
+	if err != nil {
+		return "", fmt.Errorf("error getting the caller role %s", err)
+	}
+
+	if !found {
+		return "", fmt.Errorf("the caller has no role assigned")
+	}
+
+	callerRole := role(value)
+	if !isKnownRole(callerRole) {
+		return "", fmt.Errorf("the role %s is not valid", value)
+	}
+
+	if !isGrantedRole(config, mspId, callerRole) {
+		return "", fmt.Errorf("the role %s is not granted to the msp %s", value, mspId)
+	}
+
+	return callerRole, nil
+}
+
+func isGrantedRole(config *dtos.ChaincodeConfig, mspId string, callerRole role) bool {
+	for _, grantedRole := range config.MspRoles[mspId] {
+		if role(grantedRole) == callerRole {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowedRole(callerRole role, allowedRoles []role) bool {
+	for _, allowedRole := range allowedRoles {
+		if callerRole == allowedRole {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownRole(value role) bool {
+	for _, knownRole := range readerRoles {
+		if value == knownRole {
+			return true
+		}
+	}
+	return false
+}
+
+func getCallerIdentity(context contractapi.TransactionContextInterface) (*dtos.Identity, error) {
+	identity := context.GetClientIdentity()
+
+	mspId, err := identity.GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("error getting the caller msp id %s", err)
+	}
+
+	certificate, err := identity.GetX509Certificate()
+	if err != nil {
+		return nil, fmt.Errorf("error getting the caller certificate %s", err)
+	}
+
+	if certificate == nil {
+		return nil, fmt.Errorf("the caller is not identified by a certificate")
+	}
+
+	return &dtos.Identity{
+		MspId:   mspId,
+		Subject: certificate.Subject.String(),
+	}, nil
+}
+
+func (s *SmartContract) ensureOwnerOrAdmin(context contractapi.TransactionContextInterface, callerRole role, clearId string) error {
+	if callerRole == roleAdmin {
+		return nil
+	}
+
+	asset, err := s.getDataFromLedgerById(context, clearId)
+	if err != nil {
+		return err
+	}
+
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return err
+	}
+
+	if asset.Owner != *caller {
+		return fmt.Errorf("only the owner or an admin can change the asset")
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+const (
+	configDocType    = "config"
+	configObjectType = "config"
+)
+
+const (
+	defaultMaxBatchSize  = 100
+	defaultMaxFieldsSize = 16384
+)
+
+// SetChaincodeConfig replaces the config of the chaincode, the values left out take their default
+func (s *SmartContract) SetChaincodeConfig(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request := &dtos.ChaincodeConfig{}
+	err = json.Unmarshal([]byte(encodedValue), request)
+	if err != nil {
+		return "", fmt.Errorf("error decoding the config %s", err)
+	}
+
+	config, err := validateChaincodeConfig(request)
+	if err != nil {
+		return "", err
+	}
+
+	currentConfig, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	return putChaincodeConfig(context, config, currentConfig.Version+1)
+}
+
+// GetChaincodeConfig returns the config of the chaincode with the defaults filled
+func (s *SmartContract) GetChaincodeConfig(context contractapi.TransactionContextInterface) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getChaincodeConfig(context)
+	if err != nil {
+		return "", err
+	}
+
+	configEncoded, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the config %s", err)
+	}
+
+	return string(configEncoded), nil
+}
+
+// getChaincodeConfig returns the stored config, or the defaults while no config has been set
+func getChaincodeConfig(context contractapi.TransactionContextInterface) (*dtos.ChaincodeConfig, error) {
+	configKey, err := getConfigKey(context)
+	if err != nil {
+		return nil, err
+	}
+
+	configEncoded, err := context.GetStub().GetState(configKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the config %s", err)
+	}
+
+	config := &dtos.ChaincodeConfig{}
+	if len(configEncoded) != 0 {
+		err = json.Unmarshal(configEncoded, config)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the config %s", err)
+		}
+	}
+
+	return validateChaincodeConfig(config)
+}
+
+// putChaincodeConfig stores the config with the given version, a config without admin msps would leave nobody
+// able to change it
+func putChaincodeConfig(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, version int) (string, error) {
+	if len(config.AdminMspIds) == 0 {
+		return "", fmt.Errorf("the config needs at least one admin msp id")
+	}
+
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return "", err
+	}
+
+	txTime, err := getTxTime(context)
+	if err != nil {
+		return "", err
+	}
+
+	config.DocType = configDocType
+	config.Version = version
+	config.UpdatedAt = txTime
+	config.UpdatedBy = *caller
+
+	configKey, err := getConfigKey(context)
+	if err != nil {
+		return "", err
+	}
+
+	configEncoded, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the config %s", err)
+	}
+
+	err = context.GetStub().PutState(configKey, configEncoded)
+	if err != nil {
+		return "", fmt.Errorf("error saving the config %s", err)
+	}
+
+	return string(configEncoded), nil
+}
+
+// validateChaincodeConfig normalizes the config and fills the defaults of the missing values
+func validateChaincodeConfig(config *dtos.ChaincodeConfig) (*dtos.ChaincodeConfig, error) {
+	adminMspIds := []string{}
+	for _, adminMspId := range config.AdminMspIds {
+		adminMspId = utils.RemoveStringSpaces(adminMspId)
+		if !utils.IsValidString(adminMspId) {
+			return nil, fmt.Errorf("the admin msp ids are not valid")
+		}
+		adminMspIds = append(adminMspIds, adminMspId)
+	}
+	config.AdminMspIds = adminMspIds
+
+	mspRoles := map[string][]string{}
+	for mspId, roles := range config.MspRoles {
+		mspId = utils.RemoveStringSpaces(mspId)
+		if !utils.IsValidString(mspId) {
+			return nil, fmt.Errorf("the msp roles are not valid")
+		}
+		for _, value := range roles {
+			value = strings.ToLower(utils.RemoveStringSpaces(value))
+			if !isKnownRole(role(value)) {
+				return nil, fmt.Errorf("the role %s is not valid", value)
+			}
+			mspRoles[mspId] = append(mspRoles[mspId], value)
+		}
+	}
+	config.MspRoles = mspRoles
+
+	config.DeleteMode = strings.ToLower(utils.RemoveStringSpaces(config.DeleteMode))
+	if config.DeleteMode == "" {
+		config.DeleteMode = hardDeleteMode
+	}
+	if config.DeleteMode != hardDeleteMode && config.DeleteMode != softDeleteMode {
+		return nil, fmt.Errorf("the delete mode %s is not valid", config.DeleteMode)
+	}
+
+	if config.MaxBatchSize == 0 {
+		config.MaxBatchSize = defaultMaxBatchSize
+	}
+	if config.MaxBatchSize < 0 {
+		return nil, fmt.Errorf("the max batch size %d is not valid", config.MaxBatchSize)
+	}
+
+	if config.MaxFieldsSize == 0 {
+		config.MaxFieldsSize = defaultMaxFieldsSize
+	}
+	if config.MaxFieldsSize < 0 {
+		return nil, fmt.Errorf("the max fields size %d is not valid", config.MaxFieldsSize)
+	}
+
+	config.PrivateCollection = utils.RemoveStringSpaces(config.PrivateCollection)
+
+	config.DuplicateHashPolicy = strings.ToLower(utils.RemoveStringSpaces(config.DuplicateHashPolicy))
+	if config.DuplicateHashPolicy == "" {
+		config.DuplicateHashPolicy = allowDuplicateHashPolicy
+	}
+	if config.DuplicateHashPolicy != rejectDuplicateHashPolicy && config.DuplicateHashPolicy != warnDuplicateHashPolicy && config.DuplicateHashPolicy != allowDuplicateHashPolicy {
+		return nil, fmt.Errorf("the duplicate hash policy %s is not valid", config.DuplicateHashPolicy)
+	}
+
+	return config, nil
+}
+
+func isAdminMsp(config *dtos.ChaincodeConfig, mspId string) bool {
+	for _, adminMspId := range config.AdminMspIds {
+		if adminMspId == mspId {
+			return true
+		}
+	}
+	return false
+}
+
+func getConfigKey(context contractapi.TransactionContextInterface) (string, error) {
+	configKey, err := context.GetStub().CreateCompositeKey(configObjectType, []string{})
+	if err != nil {
+		return "", fmt.Errorf("error creating the config key %s", err)
+	}
+
+	return configKey, nil
+}
@@ -0,0 +1,228 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/xeipuuv/gojsonschema"
+	"strings"
+)
+
+const (
+	formTypeDocType    = "form_type"
+	formTypeObjectType = "form_type"
+)
+
+func (s *SmartContract) RegisterFormType(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := validateFormTypeRequest(encodedValue)
+	if err != nil {
+		return "", err
+	}
+
+	formType, err := getFormType(context, request.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if formType != nil {
+		return "", fmt.Errorf("the form type %s is already registered", request.Name)
+	}
+
+	return putFormType(context, &dtos.FormType{Name: request.Name}, request)
+}
+
+func (s *SmartContract) UpdateFormType(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := validateFormTypeRequest(encodedValue)
+	if err != nil {
+		return "", err
+	}
+
+	formType, err := getFormType(context, request.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if formType == nil {
+		return "", fmt.Errorf("the form type %s is not registered", request.Name)
+	}
+
+	return putFormType(context, formType, request)
+}
+
+func (s *SmartContract) ListFormTypes(context contractapi.TransactionContextInterface) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	iterator, err := context.GetStub().GetStateByPartialCompositeKey(formTypeObjectType, []string{})
+	if err != nil {
+		return "", fmt.Errorf("error querying the form types %s", err)
+	}
+	defer iterator.Close()
+
+	formTypes := []*dtos.FormType{}
+	for iterator.HasNext() {
+		queryResponse, err := iterator.Next()
+		if err != nil {
+			return "", fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+
+		formType := &dtos.FormType{}
+		err = json.Unmarshal(queryResponse.Value, formType)
+		if err != nil {
+			return "", fmt.Errorf("error decoding the form type %s", err)
+		}
+		formTypes = append(formTypes, formType)
+	}
+
+	formTypesEncoded, err := json.Marshal(formTypes)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the form types %s", err)
+	}
+
+	return string(formTypesEncoded), nil
+}
+
+func validateFormTypeRequest(encodedValue string) (*dtos.FormTypeRequest, error) {
+	request := &dtos.FormTypeRequest{}
+	err := json.Unmarshal([]byte(encodedValue), request)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the form type %s", err)
+	}
+
+	request.Name = utils.RemoveStringSpaces(request.Name)
+	if !utils.IsValidString(request.Name) {
+		return nil, fmt.Errorf("the name is not valid")
+	}
+
+	if len(request.Schema) == 0 {
+		return nil, fmt.Errorf("the schema is missing")
+	}
+
+	_, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(request.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("the schema is not valid %s", err)
+	}
+
+	return request, nil
+}
+
+func putFormType(context contractapi.TransactionContextInterface, formType *dtos.FormType, request *dtos.FormTypeRequest) (string, error) {
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return "", err
+	}
+
+	txTime, err := getTxTime(context)
+	if err != nil {
+		return "", err
+	}
+
+	formType.DocType = formTypeDocType
+	formType.Description = request.Description
+	formType.Schema = request.Schema
+	formType.Version++
+	formType.UpdatedAt = txTime
+	formType.UpdatedBy = *caller
+
+	formTypeKey, err := getFormTypeKey(context, formType.Name)
+	if err != nil {
+		return "", err
+	}
+
+	formTypeEncoded, err := json.Marshal(formType)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the form type %s", err)
+	}
+
+	err = context.GetStub().PutState(formTypeKey, formTypeEncoded)
+	if err != nil {
+		return "", fmt.Errorf("error saving the form type %s", err)
+	}
+
+	return string(formTypeEncoded), nil
+}
+
+// getFormType returns nil when the form type is not registered
+func getFormType(context contractapi.TransactionContextInterface, name string) (*dtos.FormType, error) {
+	formTypeKey, err := getFormTypeKey(context, name)
+	if err != nil {
+		return nil, err
+	}
+
+	formTypeEncoded, err := context.GetStub().GetState(formTypeKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the form type %s", err)
+	}
+
+	if len(formTypeEncoded) == 0 {
+		return nil, nil
+	}
+
+	formType := &dtos.FormType{}
+	err = json.Unmarshal(formTypeEncoded, formType)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the form type %s", err)
+	}
+
+	return formType, nil
+}
+
+func getFormTypeKey(context contractapi.TransactionContextInterface, name string) (string, error) {
+	formTypeKey, err := context.GetStub().CreateCompositeKey(formTypeObjectType, []string{name})
+	if err != nil {
+		return "", fmt.Errorf("error creating the form type key %s", err)
+	}
+
+	return formTypeKey, nil
+}
+
+// validateAssetFormType checks that the type_form of the asset is registered and that the fields of the asset
+// match its schema, the metadata of the asset is not part of the validated document, the
+// allow_unregistered_form_types of the config turns it off while the form types of a ledger are registered
+func validateAssetFormType(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, typeForm string, fields map[string]interface{}) error {
+	if config.AllowUnregisteredFormTypes {
+		return nil
+	}
+
+	formType, err := getFormType(context, typeForm)
+	if err != nil {
+		return err
+	}
+
+	if formType == nil {
+		return fmt.Errorf("the form type %s is not registered", typeForm)
+	}
+
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(formType.Schema), gojsonschema.NewGoLoader(fields))
+	if err != nil {
+		return fmt.Errorf("error validating the asset against the schema %s", err)
+	}
+
+	if !result.Valid() {
+		schemaErrors := []string{}
+		for _, schemaError := range result.Errors() {
+			schemaErrors = append(schemaErrors, schemaError.String())
+		}
+		return fmt.Errorf("the asset doesn't match the schema of the form type %s: %s", typeForm, strings.Join(schemaErrors, ", "))
+	}
+
+	return nil
+}
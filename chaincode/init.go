@@ -1,7 +1,35 @@
 package chaincode
 
-import "github.com/hyperledger/fabric-contract-api-go/contractapi"
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
 
-func (s *SmartContract) InitLedger(context contractapi.TransactionContextInterface) error {
-	return nil
+// InitLedger seeds the config with the first admin msps, it only works while no config is stored so the chaincode
+// must be deployed with --init-required to run it before any other transaction
+func (s *SmartContract) InitLedger(context contractapi.TransactionContextInterface, encodedConfig string) error {
+	currentConfig, err := getChaincodeConfig(context)
+	if err != nil {
+		return err
+	}
+
+	if currentConfig.Version != 0 {
+		return fmt.Errorf("the config is already set")
+	}
+
+	request := &dtos.ChaincodeConfig{}
+	err = json.Unmarshal([]byte(encodedConfig), request)
+	if err != nil {
+		return fmt.Errorf("error decoding the config %s", err)
+	}
+
+	config, err := validateChaincodeConfig(request)
+	if err != nil {
+		return err
+	}
+
+	_, err = putChaincodeConfig(context, config, 1)
+	return err
 }
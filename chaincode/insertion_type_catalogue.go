@@ -0,0 +1,226 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/utils"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+const (
+	insertionTypeDocType    = "insertion_type"
+	insertionTypeObjectType = "insertion_type"
+)
+
+func (s *SmartContract) RegisterInsertionType(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := decodeInsertionTypeRequest(encodedValue)
+	if err != nil {
+		return "", err
+	}
+
+	if !utils.IsValidString(request.Label) {
+		return "", fmt.Errorf("the label is not valid")
+	}
+
+	insertionType, err := getInsertionType(context, request.Code)
+	if err != nil {
+		return "", err
+	}
+
+	if insertionType != nil {
+		return "", fmt.Errorf("the insertion type %s is already registered", request.Code)
+	}
+
+	insertionType = &dtos.InsertionType{
+		Code:   request.Code,
+		Label:  request.Label,
+		Active: true,
+	}
+	return putInsertionType(context, insertionType)
+}
+
+// UpdateInsertionType changes the label or the active flag, a deprecated value is set to inactive instead of removed
+func (s *SmartContract) UpdateInsertionType(context contractapi.TransactionContextInterface, encodedValue string) (string, error) {
+	_, err := s.authorize(context, adminRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := decodeInsertionTypeRequest(encodedValue)
+	if err != nil {
+		return "", err
+	}
+
+	if !utils.IsValidString(request.Label) && request.Active == nil {
+		return "", fmt.Errorf("nothing to change in the request")
+	}
+
+	insertionType, err := getInsertionType(context, request.Code)
+	if err != nil {
+		return "", err
+	}
+
+	if insertionType == nil {
+		return "", fmt.Errorf("the insertion type %s is not registered", request.Code)
+	}
+
+	if utils.IsValidString(request.Label) {
+		insertionType.Label = request.Label
+	}
+
+	if request.Active != nil {
+		insertionType.Active = *request.Active
+	}
+
+	return putInsertionType(context, insertionType)
+}
+
+func (s *SmartContract) ListInsertionTypes(context contractapi.TransactionContextInterface) (string, error) {
+	_, err := s.authorize(context, readerRoles...)
+	if err != nil {
+		return "", err
+	}
+
+	iterator, err := context.GetStub().GetStateByPartialCompositeKey(insertionTypeObjectType, []string{})
+	if err != nil {
+		return "", fmt.Errorf("error querying the insertion types %s", err)
+	}
+	defer iterator.Close()
+
+	insertionTypes := []*dtos.InsertionType{}
+	for iterator.HasNext() {
+		queryResponse, err := iterator.Next()
+		if err != nil {
+			return "", fmt.Errorf("error getting an item from the iterator %s", err)
+		}
+
+		insertionType := &dtos.InsertionType{}
+		err = json.Unmarshal(queryResponse.Value, insertionType)
+		if err != nil {
+			return "", fmt.Errorf("error decoding the insertion type %s", err)
+		}
+		insertionTypes = append(insertionTypes, insertionType)
+	}
+
+	insertionTypesEncoded, err := json.Marshal(insertionTypes)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the insertion types %s", err)
+	}
+
+	return string(insertionTypesEncoded), nil
+}
+
+func decodeInsertionTypeRequest(encodedValue string) (*dtos.InsertionTypeRequest, error) {
+	request := &dtos.InsertionTypeRequest{}
+	err := json.Unmarshal([]byte(encodedValue), request)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the insertion type %s", err)
+	}
+
+	request.Code = utils.RemoveStringSpaces(request.Code)
+	if !utils.IsValidString(request.Code) {
+		return nil, fmt.Errorf("the code is not valid")
+	}
+	request.Label = strings.TrimSpace(request.Label)
+
+	return request, nil
+}
+
+func putInsertionType(context contractapi.TransactionContextInterface, insertionType *dtos.InsertionType) (string, error) {
+	caller, err := getCallerIdentity(context)
+	if err != nil {
+		return "", err
+	}
+
+	txTime, err := getTxTime(context)
+	if err != nil {
+		return "", err
+	}
+
+	insertionType.DocType = insertionTypeDocType
+	insertionType.Version++
+	insertionType.UpdatedAt = txTime
+	insertionType.UpdatedBy = *caller
+
+	insertionTypeKey, err := getInsertionTypeKey(context, insertionType.Code)
+	if err != nil {
+		return "", err
+	}
+
+	insertionTypeEncoded, err := json.Marshal(insertionType)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the insertion type %s", err)
+	}
+
+	err = context.GetStub().PutState(insertionTypeKey, insertionTypeEncoded)
+	if err != nil {
+		return "", fmt.Errorf("error saving the insertion type %s", err)
+	}
+
+	return string(insertionTypeEncoded), nil
+}
+
+// getInsertionType returns nil when the insertion type is not registered
+func getInsertionType(context contractapi.TransactionContextInterface, code string) (*dtos.InsertionType, error) {
+	insertionTypeKey, err := getInsertionTypeKey(context, code)
+	if err != nil {
+		return nil, err
+	}
+
+	insertionTypeEncoded, err := context.GetStub().GetState(insertionTypeKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the insertion type %s", err)
+	}
+
+	if len(insertionTypeEncoded) == 0 {
+		return nil, nil
+	}
+
+	insertionType := &dtos.InsertionType{}
+	err = json.Unmarshal(insertionTypeEncoded, insertionType)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the insertion type %s", err)
+	}
+
+	return insertionType, nil
+}
+
+func getInsertionTypeKey(context contractapi.TransactionContextInterface, code string) (string, error) {
+	insertionTypeKey, err := context.GetStub().CreateCompositeKey(insertionTypeObjectType, []string{code})
+	if err != nil {
+		return "", fmt.Errorf("error creating the insertion type key %s", err)
+	}
+
+	return insertionTypeKey, nil
+}
+
+// validateInsertionType checks that the insertion type is registered and active, it is only called when the
+// insertion_type of an asset is set so the assets keeping a deprecated value can still be patched, the
+// allow_unregistered_insertion_types of the config turns it off while the catalogue of a ledger is filled
+func validateInsertionType(context contractapi.TransactionContextInterface, config *dtos.ChaincodeConfig, code string) error {
+	if config.AllowUnregisteredInsertionTypes {
+		return nil
+	}
+
+	insertionType, err := getInsertionType(context, code)
+	if err != nil {
+		return err
+	}
+
+	if insertionType == nil {
+		return fmt.Errorf("the insertion type %s is not registered", code)
+	}
+
+	if !insertionType.Active {
+		return fmt.Errorf("the insertion type %s is not active", code)
+	}
+
+	return nil
+}
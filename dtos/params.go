@@ -1,51 +1,295 @@
 package dtos
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type GetAllAssetsRequest struct {
-	Id            string    `json:"id"`
-	TypeForm      string    `json:"type_form"`
-	Description   string    `json:"description"`
-	Timestamp     time.Time `json:"timestamp"`
-	InsertionType string    `json:"insertion_type"`
-	Hash          string    `json:"hash"`
+	DocType           string                 `json:"doc_type"`
+	Id                string                 `json:"id"`
+	TypeForm          string                 `json:"type_form"`
+	Description       string                 `json:"description"`
+	Timestamp         time.Time              `json:"timestamp"`
+	InsertionType     string                 `json:"insertion_type"`
+	Hash              string                 `json:"hash"`
+	HashAlgorithm     string                 `json:"hash_algorithm"`
+	Fields            map[string]interface{} `json:"fields,omitempty"`
+	Owner             Identity               `json:"owner"`
+	RecordedAt        time.Time              `json:"recorded_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	UpdatedBy         Identity               `json:"updated_by"`
+	Version           int                    `json:"version"`
+	Deleted           bool                   `json:"deleted"`
+	Deletion          *Deletion              `json:"deletion,omitempty"`
+	PrivateCollection string                 `json:"private_collection,omitempty"`
+}
+
+// GetAllAssetsResponse only has Total when the filter asks for it since counting walks every matching asset
+type GetAllAssetsResponse struct {
+	Items    []*GetAllAssetsRequest `json:"items"`
+	Page     int                    `json:"page"`
+	Size     int                    `json:"size"`
+	HasMore  bool                   `json:"has_more"`
+	Bookmark string                 `json:"bookmark"`
+	Total    *int                   `json:"total,omitempty"`
 }
 
+type GetAssetsWithBookmarkResponse struct {
+	Items               []*GetAllAssetsRequest `json:"items"`
+	Bookmark            string                 `json:"bookmark"`
+	FetchedRecordsCount int32                  `json:"fetched_records_count"`
+}
+
+// PostAssetRequest with an IdempotencyKey returns the first asset again when the same request is retried
 type PostAssetRequest struct {
-	Id            string    `json:"id"`
-	TypeForm      string    `json:"type_form"`
-	Description   string    `json:"description"`
-	Timestamp     time.Time `json:"timestamp"`
-	InsertionType string    `json:"insertion_type"`
-	Hash          string    `json:"hash"`
+	Id             string                 `json:"id"`
+	TypeForm       string                 `json:"type_form"`
+	Description    string                 `json:"description"`
+	Timestamp      time.Time              `json:"timestamp"`
+	InsertionType  string                 `json:"insertion_type"`
+	Hash           string                 `json:"hash"`
+	HashAlgorithm  string                 `json:"hash_algorithm"`
+	Fields         map[string]interface{} `json:"fields,omitempty"`
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"`
 }
 
+// AssetRequest is the asset stored in the ledger, Timestamp is the time declared by the client
+// while RecordedAt and UpdatedAt are taken from the transaction timestamp, DocType tells assets
+// apart from the other documents of the state database
 type AssetRequest struct {
-	Id            string    `json:"id"`
-	TypeForm      string    `json:"type_form"`
-	Description   string    `json:"description"`
-	Timestamp     time.Time `json:"timestamp"`
-	InsertionType string    `json:"insertion_type"`
-	Hash          string    `json:"hash"`
+	DocType           string                 `json:"doc_type"`
+	Id                string                 `json:"id"`
+	TypeForm          string                 `json:"type_form"`
+	Description       string                 `json:"description"`
+	Timestamp         time.Time              `json:"timestamp"`
+	InsertionType     string                 `json:"insertion_type"`
+	Hash              string                 `json:"hash"`
+	HashAlgorithm     string                 `json:"hash_algorithm"`
+	Fields            map[string]interface{} `json:"fields,omitempty"`
+	Owner             Identity               `json:"owner"`
+	RecordedAt        time.Time              `json:"recorded_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	UpdatedBy         Identity               `json:"updated_by"`
+	Version           int                    `json:"version"`
+	Deleted           bool                   `json:"deleted"`
+	Deletion          *Deletion              `json:"deletion,omitempty"`
+	PrivateCollection string                 `json:"private_collection,omitempty"`
 }
 
+// IdempotencyRecord keeps the hash of the create request and the asset it created
+type IdempotencyRecord struct {
+	DocType     string        `json:"doc_type"`
+	Key         string        `json:"key"`
+	RequestHash string        `json:"request_hash"`
+	Asset       *AssetRequest `json:"asset"`
+}
+
+// PutAssetRequest is only applied when ExpectedVersion is missing or matches the stored version
 type PutAssetRequest struct {
-	TypeForm      string    `json:"type_form"`
-	Description   string    `json:"description"`
-	Timestamp     time.Time `json:"timestamp"`
-	InsertionType string    `json:"insertion_type"`
-	Hash          string    `json:"hash"`
+	TypeForm        string                 `json:"type_form"`
+	Description     string                 `json:"description"`
+	Timestamp       time.Time              `json:"timestamp"`
+	InsertionType   string                 `json:"insertion_type"`
+	Hash            string                 `json:"hash"`
+	HashAlgorithm   string                 `json:"hash_algorithm"`
+	Fields          map[string]interface{} `json:"fields,omitempty"`
+	ExpectedVersion *int                   `json:"expected_version,omitempty"`
+}
+
+// Deletion is the tombstone kept in the asset when it is soft deleted
+type Deletion struct {
+	Reason    string    `json:"reason"`
+	DeletedBy Identity  `json:"deleted_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type Identity struct {
+	MspId   string `json:"msp_id"`
+	Subject string `json:"subject"`
 }
 
 type Filter struct {
-	Ids            []string        `json:"ids"`
-	TypeForms      []string        `json:"type_forms"`
-	InsertionTypes []string        `json:"insertion_types"`
-	Hashs          []string        `json:"hashs"`
-	TimeFilter     TimestampFilter `json:"time_filter"`
+	Ids            []string               `json:"ids"`
+	TypeForms      []string               `json:"type_forms"`
+	InsertionTypes []string               `json:"insertion_types"`
+	Hashs          []string               `json:"hashs"`
+	HashAlgorithm  string                 `json:"hash_algorithm"`
+	TimeFilter     TimestampFilter        `json:"time_filter"`
+	IncludeTotal   bool                   `json:"include_total"`
+	IncludeDeleted bool                   `json:"include_deleted"`
+	Fields         map[string]interface{} `json:"fields"`
+	Sort           []SortField            `json:"sort"`
 }
 
+type SortField struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+// TimestampFilter targets the client declared timestamp unless Field is "recorded" or "updated"
 type TimestampFilter struct {
+	Field string    `json:"field"`
+	Min   time.Time `json:"min"`
+	Max   time.Time `json:"max"`
+}
+
+// AssetHistoryEntry is one version of an asset, Asset is nil when the key was removed by a hard delete
+type AssetHistoryEntry struct {
+	TxId      string        `json:"tx_id"`
+	Timestamp time.Time     `json:"timestamp"`
+	IsDelete  bool          `json:"is_delete"`
+	Asset     *AssetRequest `json:"asset,omitempty"`
+}
+
+// HistoryFilter limits the history to the modifications between Min and Max, both are optional
+type HistoryFilter struct {
 	Min time.Time `json:"min"`
 	Max time.Time `json:"max"`
 }
+
+type AssetHistoryPage struct {
+	Items   []*AssetHistoryEntry `json:"items"`
+	Offset  int                  `json:"offset"`
+	Limit   int                  `json:"limit"`
+	HasMore bool                 `json:"has_more"`
+}
+
+// AssetAsOfResponse has the version that was current at AsOf, Version is nil when the asset was not created yet
+type AssetAsOfResponse struct {
+	Status  string             `json:"status"`
+	AsOf    time.Time          `json:"as_of"`
+	Version *AssetHistoryEntry `json:"version,omitempty"`
+}
+
+// AssetChange has the fields changed by one transaction, Submitter is nil when the version doesn't record it
+type AssetChange struct {
+	TxId      string        `json:"tx_id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Submitter *Identity     `json:"submitter,omitempty"`
+	Operation string        `json:"operation"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// BatchItemError is the error of the item at Index of a batch, Id is empty when the item can't be decoded
+type BatchItemError struct {
+	Index int    `json:"index"`
+	Id    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// BatchSelection selects the assets of a bulk transaction by Ids or by Filter, only one of them can be set
+type BatchSelection struct {
+	Ids    []string `json:"ids"`
+	Filter *Filter  `json:"filter"`
+}
+
+type BatchResult struct {
+	Items     []*BatchItemResult `json:"items"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+}
+
+// BatchItemResult is the result of one asset of a bulk transaction, Error is only set when it failed
+type BatchItemResult struct {
+	Id            string   `json:"id"`
+	Success       bool     `json:"success"`
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	DuplicateIds  []string `json:"duplicate_ids,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type AssetBatchEvent struct {
+	Version    int                 `json:"version"`
+	Ids        []string            `json:"ids"`
+	Duplicates map[string][]string `json:"duplicates,omitempty"`
+	TxId       string              `json:"tx_id"`
+	Submitter  Identity            `json:"submitter"`
+}
+
+type AssetEvent struct {
+	Version       int      `json:"version"`
+	Id            string   `json:"id"`
+	ChangedFields []string `json:"changed_fields"`
+	DuplicateIds  []string `json:"duplicate_ids,omitempty"`
+	TxId          string   `json:"tx_id"`
+	Submitter     Identity `json:"submitter"`
+}
+
+// FormType is a registered type_form, the assets of the type are validated against Schema
+type FormType struct {
+	DocType     string          `json:"doc_type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Version     int             `json:"version"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	UpdatedBy   Identity        `json:"updated_by"`
+}
+
+type FormTypeRequest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+}
+
+// InsertionType is a catalogued insertion_type, the inactive ones are kept for the assets already using them
+type InsertionType struct {
+	DocType   string    `json:"doc_type"`
+	Code      string    `json:"code"`
+	Label     string    `json:"label"`
+	Active    bool      `json:"active"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy Identity  `json:"updated_by"`
+}
+
+// InsertionTypeRequest registers or updates an insertion type, Active is only read on update
+type InsertionTypeRequest struct {
+	Code   string `json:"code"`
+	Label  string `json:"label"`
+	Active *bool  `json:"active,omitempty"`
+}
+
+// AssetPrivateDetails is the content of an asset kept in a private data collection instead of the world state
+type AssetPrivateDetails struct {
+	DocType string                 `json:"doc_type"`
+	Id      string                 `json:"id"`
+	Content map[string]interface{} `json:"content"`
+}
+
+// DocumentVerification tells whether CandidateHash matches the hash of the asset, AnchoredTxId and AnchoredAt
+// are the transaction that stored the current hash
+type DocumentVerification struct {
+	Id            string    `json:"id"`
+	Match         bool      `json:"match"`
+	Algorithm     string    `json:"algorithm"`
+	StoredHash    string    `json:"stored_hash"`
+	CandidateHash string    `json:"candidate_hash"`
+	AnchoredTxId  string    `json:"anchored_tx_id"`
+	AnchoredAt    time.Time `json:"anchored_at"`
+}
+
+// ChaincodeConfig is the business policy of the chaincode, it is kept in the ledger so every peer endorses with
+// the same values, the zero values take the defaults
+type ChaincodeConfig struct {
+	DocType                         string              `json:"doc_type"`
+	AdminMspIds                     []string            `json:"admin_msp_ids"`
+	MspRoles                        map[string][]string `json:"msp_roles"`
+	DeleteMode                      string              `json:"delete_mode"`
+	MaxBatchSize                    int                 `json:"max_batch_size"`
+	AllowUnregisteredFormTypes      bool                `json:"allow_unregistered_form_types"`
+	AllowUnregisteredInsertionTypes bool                `json:"allow_unregistered_insertion_types"`
+	MaxFieldsSize                   int                 `json:"max_fields_size"`
+	PrivateCollection               string              `json:"private_collection"`
+	DuplicateHashPolicy             string              `json:"duplicate_hash_policy"`
+	Version                         int                 `json:"version"`
+	UpdatedAt                       time.Time           `json:"updated_at"`
+	UpdatedBy                       Identity            `json:"updated_by"`
+}
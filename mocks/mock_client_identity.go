@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hyperledger/fabric-chaincode-go/pkg/cid (interfaces: ClientIdentity)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	x509 "crypto/x509"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClientIdentity is a mock of ClientIdentity interface.
+type MockClientIdentity struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientIdentityMockRecorder
+}
+
+// MockClientIdentityMockRecorder is the mock recorder for MockClientIdentity.
+type MockClientIdentityMockRecorder struct {
+	mock *MockClientIdentity
+}
+
+// NewMockClientIdentity creates a new mock instance.
+func NewMockClientIdentity(ctrl *gomock.Controller) *MockClientIdentity {
+	mock := &MockClientIdentity{ctrl: ctrl}
+	mock.recorder = &MockClientIdentityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientIdentity) EXPECT() *MockClientIdentityMockRecorder {
+	return m.recorder
+}
+
+// AssertAttributeValue mocks base method.
+func (m *MockClientIdentity) AssertAttributeValue(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssertAttributeValue", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssertAttributeValue indicates an expected call of AssertAttributeValue.
+func (mr *MockClientIdentityMockRecorder) AssertAttributeValue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssertAttributeValue", reflect.TypeOf((*MockClientIdentity)(nil).AssertAttributeValue), arg0, arg1)
+}
+
+// GetAttributeValue mocks base method.
+func (m *MockClientIdentity) GetAttributeValue(arg0 string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttributeValue", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAttributeValue indicates an expected call of GetAttributeValue.
+func (mr *MockClientIdentityMockRecorder) GetAttributeValue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttributeValue", reflect.TypeOf((*MockClientIdentity)(nil).GetAttributeValue), arg0)
+}
+
+// GetID mocks base method.
+func (m *MockClientIdentity) GetID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetID indicates an expected call of GetID.
+func (mr *MockClientIdentityMockRecorder) GetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetID", reflect.TypeOf((*MockClientIdentity)(nil).GetID))
+}
+
+// GetMSPID mocks base method.
+func (m *MockClientIdentity) GetMSPID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMSPID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMSPID indicates an expected call of GetMSPID.
+func (mr *MockClientIdentityMockRecorder) GetMSPID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMSPID", reflect.TypeOf((*MockClientIdentity)(nil).GetMSPID))
+}
+
+// GetX509Certificate mocks base method.
+func (m *MockClientIdentity) GetX509Certificate() (*x509.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetX509Certificate")
+	ret0, _ := ret[0].(*x509.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetX509Certificate indicates an expected call of GetX509Certificate.
+func (mr *MockClientIdentityMockRecorder) GetX509Certificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetX509Certificate", reflect.TypeOf((*MockClientIdentity)(nil).GetX509Certificate))
+}
@@ -0,0 +1,140 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func newBulkRequest(id string) *dtos.PostAssetRequest {
+	return &dtos.PostAssetRequest{
+		Id:            id,
+		TypeForm:      normalTypeForm,
+		Description:   normalDescription,
+		Timestamp:     normalTxTime,
+		InsertionType: normalInsertionType,
+		Hash:          normalHash,
+	}
+}
+
+func encodeBulkRequests(t *testing.T, requests ...*dtos.PostAssetRequest) string {
+	encodedRequests, err := json.Marshal(requests)
+	assert.Nil(t, err)
+	return string(encodedRequests)
+}
+
+func Test_givenInvalidJson_whenCreateAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+
+	result, err := smartContract.CreateAssets(mockedTransaction, `{"id":"form_1"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "decoding the given values results in")
+}
+
+func Test_givenEmptyBatch_whenCreateAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+
+	result, err := smartContract.CreateAssets(mockedTransaction, "[]")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the batch is empty")
+}
+
+func Test_givenAuditor_whenCreateAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.CreateAssets(mockedTransaction, "[]")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role auditor is not allowed to perform this operation")
+}
+
+func Test_givenInvalidItems_whenCreateAssets_thenRejectTheBatchWithItemErrors(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	invalidRequest := newBulkRequest("form_3")
+	invalidRequest.Hash = ""
+	withKey := newBulkRequest("form_5")
+	withKey.IdempotencyKey = "request-1"
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(4)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return([]byte{1}, nil)
+
+	result, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t,
+		newBulkRequest("form_1"),
+		newBulkRequest("form_2"),
+		invalidRequest,
+		newBulkRequest("form_1"),
+		withKey,
+		newBulkRequest("form\x006"),
+	))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "the batch was rejected "))
+
+	itemErrors := []*dtos.BatchItemError{}
+	err = json.Unmarshal([]byte(strings.TrimPrefix(err.Error(), "the batch was rejected ")), &itemErrors)
+	assert.Nil(t, err)
+	assert.Equal(t, []*dtos.BatchItemError{
+		{Index: 1, Id: "form_2", Error: "already exists"},
+		{Index: 2, Error: "some fields are not valid"},
+		{Index: 3, Id: "form_1", Error: "the id is repeated in the item 0"},
+		{Index: 4, Id: "form_5", Error: "the idempotency key is not supported in a batch"},
+		{Index: 5, Error: "the id is not valid"},
+	}, itemErrors)
+}
+
+func Test_givenValidItems_whenCreateAssets_thenCreateAllAndEmitOneEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil).Times(2)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().PutState("form_2", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+
+	event := &dtos.AssetBatchEvent{}
+	mockedChaincodeStub.EXPECT().SetEvent("FormsCreated", gomock.Any()).DoAndReturn(func(name string, payload []byte) error {
+		return json.Unmarshal(payload, event)
+	})
+
+	resultString, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t, newBulkRequest(" form_1"), newBulkRequest("form_2")))
+	assert.Nil(t, err)
+
+	result := []*dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "form_1", result[0].Id)
+	assert.Equal(t, normalOwner, result[1].Owner)
+
+	assert.Equal(t, []string{"form_1", "form_2"}, event.Ids)
+	assert.Equal(t, normalTxId, event.TxId)
+	assert.Equal(t, normalOwner, event.Submitter)
+}
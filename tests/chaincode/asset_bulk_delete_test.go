@@ -0,0 +1,116 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_givenEditor_whenDeleteAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1"]}`, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role editor is not allowed to perform this operation")
+}
+
+func Test_givenSoftModeWithoutReason_whenDeleteAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DeleteMode: "soft"})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1"]}`, " ")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the reason is not valid")
+}
+
+func Test_givenEmptyIds_whenDeleteAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":[]}`, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the batch is empty")
+}
+
+func Test_givenIdWithNullCharacter_whenDeleteAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1","\u0000idempotency\u0000Org1MSP\u0000key\u0000"]}`, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+}
+
+func Test_givenIds_whenDeleteAssets_thenDeleteEachAndReportFailures(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().DelState("form_1").Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, "form_1")).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+
+	event := &dtos.AssetBatchEvent{}
+	mockedChaincodeStub.EXPECT().SetEvent("FormsDeleted", gomock.Any()).DoAndReturn(func(name string, payload []byte) error {
+		return json.Unmarshal(payload, event)
+	})
+
+	resultString, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1","form_2"]}`, "")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"items":[{"id":"form_1","success":true},{"id":"form_2","success":false,"error":"the asset doesn't exist"}],"succeeded":1,"failed":1}`, resultString)
+	assert.Equal(t, []string{"form_1"}, event.Ids)
+}
+
+func Test_givenSoftMode_whenDeleteAssets_thenKeepTombstones(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DeleteMode: "soft"})
+
+	storedAsset := &dtos.AssetRequest{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		return json.Unmarshal(value, storedAsset)
+	})
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormsDeleted", gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1"]}`, "duplicated")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"items":[{"id":"form_1","success":true,"changed_fields":["deleted","deletion"]}],"succeeded":1,"failed":0}`, resultString)
+	assert.True(t, storedAsset.Deleted)
+	assert.Equal(t, "duplicated", storedAsset.Deletion.Reason)
+}
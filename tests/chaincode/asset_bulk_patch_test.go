@@ -0,0 +1,144 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_givenIdsAndFilter_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_1"],"filter":{}}`, `{"hash":"`+newHash+`"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the selection needs either ids or a filter")
+}
+
+func Test_givenExpectedVersion_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_1"]}`, `{"hash":"`+newHash+`","expected_version":1}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the expected version is not supported in a batch")
+}
+
+func Test_givenTooManyIds_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{MaxBatchSize: 2})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_1","form_2","form_3"]}`, `{"hash":"`+newHash+`"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the batch has 3 items, the maximum is 2")
+}
+
+func Test_givenFilterMatchingTooManyAssets_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{MaxBatchSize: 1})
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(3)
+	mockedChaincodeStub.EXPECT().GetQueryResult(`{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"type_form":{"$in":["`+normalTypeForm+`"]}}}`).Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(2)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_1"}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_2"}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"filter":{"type_forms":["`+normalTypeForm+`"]}}`, `{"hash":"`+newHash+`"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the filter matches more than 1 assets")
+}
+
+func Test_givenIds_whenPatchAssets_thenPatchEachAndReportFailures(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+
+	event := &dtos.AssetBatchEvent{}
+	mockedChaincodeStub.EXPECT().SetEvent("FormsPatched", gomock.Any()).DoAndReturn(func(name string, payload []byte) error {
+		return json.Unmarshal(payload, event)
+	})
+
+	resultString, err := smartContract.PatchAssets(mockedTransaction, `{"ids":[" form_1","form_2","form_1"]}`, `{"hash":"`+newHash+`"}`)
+	assert.Nil(t, err)
+
+	result := &dtos.BatchResult{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, &dtos.BatchResult{
+		Items: []*dtos.BatchItemResult{
+			{Id: "form_1", Success: true, ChangedFields: []string{"hash"}},
+			{Id: "form_2", Error: "the asset doesn't exist"},
+			{Id: "form_1", Error: "the id is repeated"},
+		},
+		Succeeded: 1,
+		Failed:    2,
+	}, result)
+	assert.Equal(t, []string{"form_1"}, event.Ids)
+}
+
+func Test_givenNoPatchedAsset_whenPatchAssets_thenNoEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(3)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+
+	resultString, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_2"]}`, `{"hash":"`+newHash+`"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"items":[{"id":"form_2","success":false,"error":"the asset doesn't exist"}],"succeeded":0,"failed":1}`, resultString)
+}
+
+func Test_givenErrorMovingTheHashIndex_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("hash~id", gomock.Any()).Return("", fmt.Errorf("some exception"))
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_1"]}`, `{"hash":"`+newHash+`"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error creating the hash index key")
+}
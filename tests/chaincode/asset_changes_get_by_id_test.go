@@ -0,0 +1,130 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+var editorChanges = dtos.Identity{MspId: "Org2MSP", Subject: "CN=user2"}
+
+func mockHistory(mockedHistoryIterator *mocks.MockHistoryQueryIteratorInterface, history []*queryresult.KeyModification) {
+	for _, item := range history {
+		mockedHistoryIterator.EXPECT().HasNext().Return(true)
+		mockedHistoryIterator.EXPECT().Next().Return(item, nil)
+	}
+	mockedHistoryIterator.EXPECT().HasNext().Return(false)
+	mockedHistoryIterator.EXPECT().Close().Return(nil)
+}
+
+func encodeHistoryValue(t *testing.T, asset *dtos.AssetRequest) []byte {
+	encodedAsset, err := json.Marshal(asset)
+	assert.Nil(t, err)
+	return encodedAsset
+}
+
+func Test_givenInvalidId_whenGetAssetChangesById_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAssetChangesById(mockedTransaction, "  ")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+}
+
+func Test_givenErrorGettingHistory_whenGetAssetChangesById_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(nil, fmt.Errorf("some exception"))
+
+	result, err := smartContract.GetAssetChangesById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "something went wrong getting the item history")
+}
+
+func Test_givenNoHistory_whenGetAssetChangesById_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIterator, nil)
+	mockHistory(mockedHistoryIterator, nil)
+
+	result, err := smartContract.GetAssetChangesById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset doesn't exist")
+}
+
+func Test_givenFullLifecycle_whenGetAssetChangesById_thenReturnChangesInOrder(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	cleanId := utils.RemoveStringSpaces(normalId)
+	created := &dtos.AssetRequest{Id: cleanId, Hash: "hash_1", Owner: normalOwner, UpdatedBy: normalOwner}
+	patched := &dtos.AssetRequest{Id: cleanId, Hash: "hash_2", Owner: normalOwner, UpdatedBy: editorChanges}
+	deleted := &dtos.AssetRequest{Id: cleanId, Hash: "hash_2", Owner: normalOwner, UpdatedBy: normalOwner, Deleted: true,
+		Deletion: &dtos.Deletion{Reason: "duplicated form", DeletedBy: normalOwner}}
+	restored := &dtos.AssetRequest{Id: cleanId, Hash: "hash_2", Owner: normalOwner, UpdatedBy: normalOwner}
+
+	// fabric returns the newest modification first
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(cleanId).Return(mockedHistoryIterator, nil)
+	mockHistory(mockedHistoryIterator, []*queryresult.KeyModification{
+		{TxId: "tx_5", Timestamp: timestamppb.New(normalTxTime.Add(4 * time.Hour)), IsDelete: true},
+		{TxId: "tx_4", Timestamp: timestamppb.New(normalTxTime.Add(3 * time.Hour)), Value: encodeHistoryValue(t, restored)},
+		{TxId: "tx_3", Timestamp: timestamppb.New(normalTxTime.Add(2 * time.Hour)), Value: encodeHistoryValue(t, deleted)},
+		{TxId: "tx_2", Timestamp: timestamppb.New(normalTxTime.Add(time.Hour)), Value: encodeHistoryValue(t, patched)},
+		{TxId: "tx_1", Timestamp: normalTxTimestamp, Value: encodeHistoryValue(t, created)},
+	})
+
+	result, err := smartContract.GetAssetChangesById(mockedTransaction, normalId)
+	assert.Nil(t, err)
+
+	changes := []*dtos.AssetChange{}
+	err = json.Unmarshal([]byte(result), &changes)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(changes))
+
+	operations := []string{}
+	for _, change := range changes {
+		operations = append(operations, change.Operation)
+	}
+	assert.Equal(t, []string{"create", "update", "delete", "restore", "delete"}, operations)
+
+	assert.Equal(t, "tx_1", changes[0].TxId)
+	assert.Equal(t, normalTxTime, changes[0].Timestamp)
+	assert.Equal(t, &normalOwner, changes[0].Submitter)
+	assert.Contains(t, changes[0].Changes, dtos.FieldChange{Field: "hash", OldValue: nil, NewValue: "hash_1"})
+
+	assert.Equal(t, "tx_2", changes[1].TxId)
+	assert.Equal(t, &editorChanges, changes[1].Submitter)
+	assert.Equal(t, []dtos.FieldChange{{Field: "hash", OldValue: "hash_1", NewValue: "hash_2"}}, changes[1].Changes)
+
+	assert.Equal(t, "deleted", changes[2].Changes[0].Field)
+	assert.Equal(t, "deletion", changes[2].Changes[1].Field)
+
+	assert.Nil(t, changes[4].Submitter)
+	assert.Contains(t, changes[4].Changes, dtos.FieldChange{Field: "hash", OldValue: "hash_2", NewValue: nil})
+}
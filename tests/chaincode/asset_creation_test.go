@@ -17,11 +17,12 @@ var normalTypeFormCreation = "some_ type_form"
 var normalDescriptionCreation = "some_ description"
 var normalTimestampCreation = time.Now()
 var normalInsertionTypeCreation = "s o me_insertion_type"
-var normalHashCreation = "som e _has h"
+var normalHashCreation = "7c84 8c0e96c338ba4e621bfba064e94f2670b4cd1bf0fc803b42d4332189cba5 "
 
 func Test_givenNilAsset_whenCreateAsset_thenReturnError(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedStub := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedStub, "submitter")
 
 	result, err := smartContract.CreateAsset(mockedStub, "")
 	assert.Equal(t, "", result)
@@ -31,6 +32,7 @@ func Test_givenNilAsset_whenCreateAsset_thenReturnError(t *testing.T) {
 func Test_givenCompleteObjectWithEmtpyStrings_whenCreateAsset_thenReturnError(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedStub := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedStub, "submitter")
 
 	request := &dtos.PostAssetRequest{
 		Id:            normalIdCreation,
@@ -48,9 +50,32 @@ func Test_givenCompleteObjectWithEmtpyStrings_whenCreateAsset_thenReturnError(t
 	assert.Equal(t, "", result)
 }
 
+func Test_givenIdWithNullCharacter_whenCreateAsset_thenReturnError(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedStub := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedStub, "submitter")
+
+	request := &dtos.PostAssetRequest{
+		Id:            "\x00config\x00",
+		TypeForm:      normalTypeFormCreation,
+		Description:   normalDescriptionCreation,
+		Timestamp:     normalTimestampCreation,
+		InsertionType: normalInsertionTypeCreation,
+		Hash:          normalHashCreation,
+	}
+	encodedData, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	result, err := smartContract.CreateAsset(mockedStub, string(encodedData))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the id is not valid", err.Error())
+}
+
 func Test_givenAlreadyExistentObject_whenCreateAsset_thenReturnError(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 
 	request := &dtos.PostAssetRequest{
@@ -75,7 +100,10 @@ func Test_givenAlreadyExistentObject_whenCreateAsset_thenReturnError(t *testing.
 func Test_givenCompleteValidObject_whenCreateAsset_thenReturnSameObject(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
 
 	request := &dtos.PostAssetRequest{
 		Id:            normalIdCreation,
@@ -88,25 +116,37 @@ func Test_givenCompleteValidObject_whenCreateAsset_thenReturnSameObject(t *testi
 	encodedData, err := json.Marshal(request)
 	assert.Nil(t, err)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(10)
 	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalIdCreation)).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
 
-	cleanRequest := &dtos.PostAssetRequest{
+	cleanRequest := &dtos.AssetRequest{
 		Id:            utils.RemoveStringSpaces(normalIdCreation),
 		TypeForm:      utils.RemoveStringSpaces(normalTypeFormCreation),
 		Description:   normalDescriptionCreation,
 		Timestamp:     normalTimestampCreation,
 		InsertionType: utils.RemoveStringSpaces(normalInsertionTypeCreation),
 		Hash:          utils.RemoveStringSpaces(normalHashCreation),
+		HashAlgorithm: "sha256",
+		Owner:         normalOwner,
+		RecordedAt:    normalTxTime,
+		UpdatedAt:     normalTxTime,
+		UpdatedBy:     normalOwner,
+		Version:       1,
+		DocType:       "form",
 	}
 	cleanEncodedData, err := json.Marshal(cleanRequest)
 	assert.Nil(t, err)
 
 	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalIdCreation), cleanEncodedData).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(cleanRequest.Hash, cleanRequest.Id), []byte{0x00}).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil)
 	resultString, err := smartContract.CreateAsset(mockedTransaction, string(encodedData))
 	assert.Nil(t, err)
 
-	result := &dtos.PostAssetRequest{}
+	result := &dtos.AssetRequest{}
 	err = json.Unmarshal([]byte(resultString), result)
 	assert.Nil(t, err)
 
@@ -116,12 +156,18 @@ func Test_givenCompleteValidObject_whenCreateAsset_thenReturnSameObject(t *testi
 	assert.Equal(t, result.Timestamp.Equal(cleanRequest.Timestamp), true)
 	assert.Equal(t, result.InsertionType, cleanRequest.InsertionType)
 	assert.Equal(t, result.Hash, cleanRequest.Hash)
+	assert.Equal(t, result.Owner, normalOwner)
+	assert.Equal(t, result.RecordedAt.Equal(normalTxTime), true)
+	assert.Equal(t, result.UpdatedAt.Equal(normalTxTime), true)
 }
 
 func Test_givenExceptionOnPut_whenCreateAsset_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
 
 	request := &dtos.PostAssetRequest{
 		Id:            normalIdCreation,
@@ -134,16 +180,24 @@ func Test_givenExceptionOnPut_whenCreateAsset_thenReturnException(t *testing.T)
 	encodedData, err := json.Marshal(request)
 	assert.Nil(t, err)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(6)
 	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalIdCreation)).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
 
-	cleanRequest := &dtos.PostAssetRequest{
+	cleanRequest := &dtos.AssetRequest{
 		Id:            utils.RemoveStringSpaces(normalIdCreation),
 		TypeForm:      utils.RemoveStringSpaces(normalTypeFormCreation),
 		Description:   normalDescriptionCreation,
 		Timestamp:     normalTimestampCreation,
 		InsertionType: utils.RemoveStringSpaces(normalInsertionTypeCreation),
 		Hash:          utils.RemoveStringSpaces(normalHashCreation),
+		HashAlgorithm: "sha256",
+		Owner:         normalOwner,
+		RecordedAt:    normalTxTime,
+		UpdatedAt:     normalTxTime,
+		UpdatedBy:     normalOwner,
+		Version:       1,
+		DocType:       "form",
 	}
 	cleanEncodedData, err := json.Marshal(cleanRequest)
 	assert.Nil(t, err)
@@ -1,7 +1,9 @@
 package chaincode
 
 import (
+	"encoding/json"
 	"fmt"
+	"form-chaincode/dtos"
 	"form-chaincode/mocks"
 	"form-chaincode/utils"
 	"github.com/golang/mock/gomock"
@@ -14,8 +16,9 @@ var invalidIdDelete = "   "
 func Test_givenInvalidId_when_DeleteAssetById_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
 
-	result, err := smartContract.DeleteAssetById(mockedTransaction, invalidIdDelete)
+	result, err := smartContract.DeleteAssetById(mockedTransaction, invalidIdDelete, "", "")
 	assert.NotNil(t, result)
 	assert.Equal(t, result, false)
 	assert.NotNil(t, err)
@@ -25,12 +28,13 @@ func Test_givenInvalidId_when_DeleteAssetById_thenReturnException(t *testing.T)
 func Test_givenIdForNonExistentAsset_whenDeleteAssetById_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
 	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(nil, nil)
 
-	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId)
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
 	assert.NotNil(t, result)
 	assert.Equal(t, result, false)
 	assert.NotNil(t, err)
@@ -40,13 +44,19 @@ func Test_givenIdForNonExistentAsset_whenDeleteAssetById_thenReturnException(t *
 func Test_givenValidId_whenDeleteAssetById_thenSuccess(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
-	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{1, 0, 1}, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(9)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId)), nil).Times(2)
 
 	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
-	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(nil)
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
 	assert.NotNil(t, result)
 	assert.Equal(t, result, true)
 	assert.Nil(t, err)
@@ -55,15 +65,103 @@ func Test_givenValidId_whenDeleteAssetById_thenSuccess(t *testing.T) {
 func Test_givenLedgerError_whenDeleteAssetById_thenError(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
-	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{1, 0, 1}, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(5)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId)), nil).Times(2)
 
 	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(fmt.Errorf("SOME EXCEPTION"))
-	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId)
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
 	assert.NotNil(t, result)
 	assert.Equal(t, result, false)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "error deleting state from the ledger")
 }
+
+func Test_givenSoftDeleteAndNoReason_whenDeleteAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DeleteMode: "soft"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(3)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{1, 0, 1}, nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "  ", "")
+	assert.Equal(t, result, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the reason is not valid")
+}
+
+func Test_givenSoftDeleteAndDeletedAsset_whenDeleteAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DeleteMode: "soft"})
+
+	storedAsset := &dtos.AssetRequest{
+		Id:      utils.RemoveStringSpaces(normalId),
+		Deleted: true,
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(4)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).Times(2)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "duplicated form", "")
+	assert.Equal(t, result, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset is already deleted")
+}
+
+func Test_givenSoftDelete_whenDeleteAssetById_thenKeepTombstone(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DeleteMode: "soft"})
+
+	storedAsset := &dtos.AssetRequest{
+		Id:    utils.RemoveStringSpaces(normalId),
+		Hash:  normalHash,
+		Owner: normalOwner,
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	expectedAsset := &dtos.AssetRequest{
+		Id:        utils.RemoveStringSpaces(normalId),
+		Hash:      normalHash,
+		Owner:     normalOwner,
+		UpdatedAt: normalTxTime,
+		UpdatedBy: normalOwner,
+		Version:   1,
+		DocType:   "form",
+		Deleted:   true,
+		Deletion: &dtos.Deletion{
+			Reason:    "duplicated form",
+			DeletedBy: normalOwner,
+			DeletedAt: normalTxTime,
+		},
+	}
+	expectedEncodedAsset, err := json.Marshal(expectedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalId), expectedEncodedAsset).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormDeleted", event)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, " duplicated form ", "")
+	assert.Equal(t, result, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"deleted", "deletion"}, event.ChangedFields)
+}
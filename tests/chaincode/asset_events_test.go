@@ -0,0 +1,147 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func captureEvent(mockedChaincodeStub *mocks.MockChaincodeStubInterface, name string, event *dtos.AssetEvent) {
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent(name, gomock.Any()).DoAndReturn(func(name string, payload []byte) error {
+		return json.Unmarshal(payload, event)
+	})
+}
+
+func Test_givenValidObject_whenCreateAsset_thenEmitFormCreated(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	request := &dtos.PostAssetRequest{
+		Id:            normalIdCreation,
+		TypeForm:      normalTypeFormCreation,
+		Description:   normalDescriptionCreation,
+		Timestamp:     normalTimestampCreation,
+		InsertionType: normalInsertionTypeCreation,
+		Hash:          normalHashCreation,
+	}
+	encodedData, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalIdCreation)).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalIdCreation), gomock.Any()).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormCreated", event)
+
+	_, err = smartContract.CreateAsset(mockedTransaction, string(encodedData))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, event.Version)
+	assert.Equal(t, utils.RemoveStringSpaces(normalIdCreation), event.Id)
+	assert.Equal(t, normalTxId, event.TxId)
+	assert.Equal(t, normalOwner, event.Submitter)
+	assert.Equal(t, []string{"deleted", "description", "hash", "hash_algorithm", "id", "insertion_type", "owner", "timestamp", "type_form"}, event.ChangedFields)
+}
+
+func Test_givenChangedHash_whenPatchAsset_thenEmitFormPatchedWithChangedFields(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	assetToPut := &dtos.PutAssetRequest{
+		Hash:     newHash,
+		TypeForm: normalTypeForm,
+	}
+	encoded, err := json.Marshal(assetToPut)
+	assert.Nil(t, err)
+
+	storedAsset := &dtos.AssetRequest{
+		Id:            utils.RemoveStringSpaces(normalId),
+		TypeForm:      normalTypeForm,
+		Hash:          normalHash,
+		HashAlgorithm: "sha256",
+		Owner:         normalOwner,
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalId), gomock.Any()).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormPatched", event)
+
+	_, err = smartContract.PatchAsset(mockedTransaction, string(encoded), normalId)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, event.Version)
+	assert.Equal(t, utils.RemoveStringSpaces(normalId), event.Id)
+	assert.Equal(t, normalTxId, event.TxId)
+	assert.Equal(t, normalOwner, event.Submitter)
+	assert.Equal(t, []string{"hash"}, event.ChangedFields)
+}
+
+func Test_givenValidId_whenDeleteAssetById_thenEmitFormDeleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormDeleted", event)
+
+	_, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, event.Version)
+	assert.Equal(t, utils.RemoveStringSpaces(normalId), event.Id)
+	assert.Equal(t, normalTxId, event.TxId)
+	assert.Equal(t, normalOwner, event.Submitter)
+	assert.Equal(t, []string{}, event.ChangedFields)
+}
+
+func Test_givenErrorSettingEvent_whenDeleteAssetById_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(fmt.Errorf("some exception"))
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
+	assert.Equal(t, false, result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error setting the event")
+}
@@ -0,0 +1,143 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_givenInvalidFieldKey_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+
+	request := newBulkRequest("form_1")
+	request.Fields = map[string]interface{}{"applicant": map[string]interface{}{"first.name": "Ana"}}
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the field key first.name is not valid")
+}
+
+func Test_givenTooLargeFields_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{MaxFieldsSize: 20})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+
+	request := newBulkRequest("form_1")
+	request.Fields = map[string]interface{}{"comment": strings.Repeat("a", 20)}
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the fields are 34 bytes, the maximum is 20")
+}
+
+func Test_givenFields_whenCreateAsset_thenStoreThem(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	request := newBulkRequest("form_1")
+	request.Fields = map[string]interface{}{"age": 42.0, "applicant": map[string]interface{}{"country": "FR"}, "signed": true}
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	storedAsset := &dtos.AssetRequest{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		return json.Unmarshal(value, storedAsset)
+	})
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil)
+
+	_, err = smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Nil(t, err)
+	assert.Equal(t, request.Fields, storedAsset.Fields)
+}
+
+func Test_givenFields_whenPatchAsset_thenMergeThemAndRemoveNulls(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	stored := &dtos.AssetRequest{
+		Id:       "form_1",
+		TypeForm: normalTypeForm,
+		Hash:     normalHash,
+		Owner:    normalOwner,
+		Fields:   map[string]interface{}{"age": 42.0, "comment": "first", "signed": false},
+	}
+	encodedStored, err := json.Marshal(stored)
+	assert.Nil(t, err)
+
+	event := &dtos.AssetEvent{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodedStored, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	captureEvent(mockedChaincodeStub, "FormPatched", event)
+
+	resultString, err := smartContract.PatchAsset(mockedTransaction, `{"fields":{"comment":null,"signed":true}}`, "form_1")
+	assert.Nil(t, err)
+
+	result := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"age": 42.0, "signed": true}, result.Fields)
+	assert.Equal(t, []string{"fields"}, event.ChangedFields)
+}
+
+func Test_givenFieldPaths_whenCreateQuery_thenMatchEachPath(t *testing.T) {
+	query := captureQuery(t, &dtos.Filter{
+		TypeForms: []string{normalTypeForm},
+		Fields:    map[string]interface{}{"applicant.country": "FR", "age": 42},
+	})
+
+	selector := query["selector"].(map[string]interface{})
+	assert.Equal(t, "FR", selector["fields.applicant.country"])
+	assert.Equal(t, 42.0, selector["fields.age"])
+}
+
+func Test_givenInvalidFieldPath_whenGetAllAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", `{"fields":{"applicant..country":"FR"}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the field path applicant..country is not valid")
+}
+
+func Test_givenObjectFieldValue_whenGetAllAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", `{"fields":{"applicant":{"$gt":null}}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the field path applicant can only be compared with a string, a number or a boolean")
+}
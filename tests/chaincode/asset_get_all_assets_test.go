@@ -1,6 +1,8 @@
 package chaincode
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"form-chaincode/dtos"
@@ -10,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -17,6 +20,7 @@ import (
 func Test_GivenInvalidPageChar_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "l", "10", "kkkk")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -25,6 +29,7 @@ func Test_GivenInvalidPageChar_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenInvalidSizeChar_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "0", "l", "kkkk")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -33,6 +38,7 @@ func Test_GivenInvalidSizeChar_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenInvalidPage_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "-1", "10", "kkkk")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -42,6 +48,7 @@ func Test_GivenInvalidPage_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenInvalidSize_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "0", "-1", "kkk")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -50,6 +57,7 @@ func Test_GivenInvalidSize_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenInvalidSize2_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "0", "0", "kkk")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -58,6 +66,7 @@ func Test_GivenInvalidSize2_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenNilFilter_whenGetAllAssets_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	assets, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", "")
 	assert.Equal(t, assets, "")
 	assert.NotNil(t, err)
@@ -67,12 +76,13 @@ func Test_GivenNilFilter_whenGetAllAssets_thenException(t *testing.T) {
 func Test_GivenEmptyFilterAndErrorIterating_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	filter := &dtos.Filter{}
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -87,13 +97,14 @@ func Test_GivenEmptyFilterAndErrorIterating_whenGetAllAssets_thenReturnException
 func Test_GivenEmptyFilterAndOneSizePage_whenGetAllAssets_thenReturnOneItem(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 	filter := &dtos.Filter{}
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -123,13 +134,13 @@ func Test_GivenEmptyFilterAndOneSizePage_whenGetAllAssets_thenReturnOneItem(t *t
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
-	assert.Equal(t, len(*assets), 1)
+	assert.Equal(t, len(response.Items), 1)
 
-	singleAsset := (*assets)[0]
+	singleAsset := response.Items[0]
 	assert.Equal(t, asset.Id, singleAsset.Id)
 	assert.Equal(t, asset.TypeForm, singleAsset.TypeForm)
 	assert.Equal(t, asset.Description, singleAsset.Description)
@@ -138,16 +149,81 @@ func Test_GivenEmptyFilterAndOneSizePage_whenGetAllAssets_thenReturnOneItem(t *t
 	assert.Equal(t, asset.Hash, singleAsset.Hash)
 }
 
+func Test_GivenBase64HashWithAlgorithm_whenGetAllAssets_thenQueryTheHexDigest(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	digest, err := hex.DecodeString(normalHash)
+	assert.Nil(t, err)
+	filter := &dtos.Filter{
+		Hashs:         []string{" " + base64.StdEncoding.EncodeToString(digest), strings.ToUpper(normalHash)},
+		HashAlgorithm: "SHA-256",
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + normalHash + `","` + base64.StdEncoding.EncodeToString(digest) + `","` + normalHash + `","` + strings.ToUpper(normalHash) + `"]}}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(nil, nil, fmt.Errorf("some exception"))
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error querying the ledger")
+}
+
+func Test_GivenLegacyHash_whenGetAllAssets_thenQueryItAsGiven(t *testing.T) {
+	selector := captureQuery(t, &dtos.Filter{Hashs: []string{" legacy-hash", normalHash}, HashAlgorithm: "sha384"})["selector"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{"$in": []interface{}{"legacy-hash", normalHash}}, selector["hash"])
+}
+
+func Test_GivenUnsupportedHashAlgorithm_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	encodedFilter, err := json.Marshal(&dtos.Filter{Hashs: []string{normalHash}, HashAlgorithm: "md5"})
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the hash algorithm md5 is not supported", err.Error())
+}
+
+func Test_GivenLegacyAssetWithoutDocType_whenGetAllAssets_thenTheSelectorMatchesIt(t *testing.T) {
+	selector := captureQuery(t, &dtos.Filter{IncludeDeleted: true})["selector"].(map[string]interface{})
+
+	legacyAsset := map[string]interface{}{"id": normalId, "type_form": normalTypeForm, "hash": normalHash}
+	assert.True(t, matchesSelector(t, selector, legacyAsset))
+	assert.True(t, matchesSelector(t, selector, map[string]interface{}{"id": normalId, "doc_type": "form"}))
+	assert.False(t, matchesSelector(t, selector, map[string]interface{}{"doc_type": "idempotency"}))
+	assert.False(t, matchesSelector(t, selector, map[string]interface{}{"doc_type": "config"}))
+}
+
+func Test_GivenAssetWithoutDeleted_whenGetAllAssets_thenTheSelectorMatchesIt(t *testing.T) {
+	selector := captureQuery(t, &dtos.Filter{})["selector"].(map[string]interface{})
+
+	assert.True(t, matchesSelector(t, selector, map[string]interface{}{"id": normalId}))
+	assert.True(t, matchesSelector(t, selector, map[string]interface{}{"id": normalId, "doc_type": "form", "deleted": false}))
+	assert.False(t, matchesSelector(t, selector, map[string]interface{}{"id": normalId, "doc_type": "form", "deleted": true}))
+	assert.False(t, matchesSelector(t, selector, map[string]interface{}{"doc_type": "idempotency"}))
+}
+
 func Test_GivenEmptyFilterAndFiveSizePage_whenGetAllAssets_thenReturnFiveItems(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 	filter := &dtos.Filter{}
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -177,23 +253,24 @@ func Test_GivenEmptyFilterAndFiveSizePage_whenGetAllAssets_thenReturnFiveItems(t
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "0", "5", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
-	assert.Equal(t, len(*assets), 5)
+	assert.Equal(t, len(response.Items), 5)
 }
 
 func Test_GivenEmptyFilterAndNextpageSizeOne_whenGetAllAssets_thenReturnOneItem(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 	filter := &dtos.Filter{}
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
 
@@ -226,25 +303,26 @@ func Test_GivenEmptyFilterAndNextpageSizeOne_whenGetAllAssets_thenReturnOneItem(
 	mockedIterator.EXPECT().Close().Return(nil).Times(2)
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 1)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 1)
 }
 
 func Test_GivenEmptyFilterAndHasNextFalseSizeOne_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 	filter := &dtos.Filter{}
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -257,18 +335,19 @@ func Test_GivenEmptyFilterAndHasNextFalseSizeOne_whenGetAllAssets_thenReturnExce
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
 }
 
 func Test_GivenHashFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 
@@ -281,7 +360,7 @@ func Test_GivenHashFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -294,18 +373,19 @@ func Test_GivenHashFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
 }
 
 func Test_GivenHashAndIdsFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 
@@ -322,7 +402,7 @@ func Test_GivenHashAndIdsFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -335,18 +415,19 @@ func Test_GivenHashAndIdsFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
 }
 
 func Test_GivenHashAndIdsAndTypeFormsFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 
@@ -367,7 +448,7 @@ func Test_GivenHashAndIdsAndTypeFormsFilterAndNoItems_whenGetAllAssets_thenArgsO
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -380,18 +461,19 @@ func Test_GivenHashAndIdsAndTypeFormsFilterAndNoItems_whenGetAllAssets_thenArgsO
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
 }
 
 func Test_GivenHashAndIdsAndTypeFormsAndInsertionTypesFilterAndNoItems_whenGetAllAssets_thenArgsOk(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 
@@ -416,7 +498,7 @@ func Test_GivenHashAndIdsAndTypeFormsAndInsertionTypesFilterAndNoItems_whenGetAl
 	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
-	expectedQuery := `{"selector":{"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"insertion_type":{"$in":["` + utils.RemoveStringSpaces(normalInsertionType) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"insertion_type":{"$in":["` + utils.RemoveStringSpaces(normalInsertionType) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]` + `}}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -429,18 +511,19 @@ func Test_GivenHashAndIdsAndTypeFormsAndInsertionTypesFilterAndNoItems_whenGetAl
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
 }
 
 func Test_GivenMaxInvalidAndMaxValidTimestamp_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	filter := &dtos.Filter{
 		TimeFilter: dtos.TimestampFilter{
 			Min: normalTimestamp,
@@ -458,6 +541,7 @@ func Test_GivenMaxInvalidAndMaxValidTimestamp_whenGetAllAssets_thenReturnExcepti
 func Test_GivenMinInvalidAndMaxValidTimestamp_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	filter := &dtos.Filter{
 		TimeFilter: dtos.TimestampFilter{
 			Max: normalTimestamp,
@@ -475,6 +559,7 @@ func Test_GivenMinInvalidAndMaxValidTimestamp_whenGetAllAssets_thenReturnExcepti
 func Test_GivenMinAndMaxEqual_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	filter := &dtos.Filter{
 		TimeFilter: dtos.TimestampFilter{
 			Max: normalTimestamp,
@@ -493,6 +578,7 @@ func Test_GivenMinAndMaxEqual_whenGetAllAssets_thenReturnException(t *testing.T)
 func Test_GivenMinNotInferiorToMax_whenGetAllAssets_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	filter := &dtos.Filter{
 		TimeFilter: dtos.TimestampFilter{
 			Max: normalTimestamp,
@@ -511,6 +597,7 @@ func Test_GivenMinNotInferiorToMax_whenGetAllAssets_thenReturnException(t *testi
 func Test_GivenValidFilter_whenGetAllAssets_thenQueryValid(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
 
@@ -542,7 +629,7 @@ func Test_GivenValidFilter_whenGetAllAssets_thenQueryValid(t *testing.T) {
 	minimumEncoded, _ := json.Marshal(filter.TimeFilter.Min)
 	maximumEncoded, _ := json.Marshal(filter.TimeFilter.Max)
 
-	expectedQuery := `{"selector":{"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"insertion_type":{"$in":["` + utils.RemoveStringSpaces(normalInsertionType) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]}` + `,"timestamp":{"$gte":` + string(minimumEncoded) + `,` + `"$lte":` + string(maximumEncoded) + `}}}`
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"hash":{"$in":["` + utils.RemoveStringSpaces(normalHash) + `"]}` + `,"type_form":{"$in":["` + utils.RemoveStringSpaces(normalTypeForm) + `"]}` + `,"insertion_type":{"$in":["` + utils.RemoveStringSpaces(normalInsertionType) + `"]}` + `,"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]}` + `,"timestamp":{"$gte":` + string(minimumEncoded) + `,` + `"$lte":` + string(maximumEncoded) + `}}}`
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 
@@ -555,11 +642,380 @@ func Test_GivenValidFilter_whenGetAllAssets_thenQueryValid(t *testing.T) {
 
 	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "1", "1", string(encodedFilter))
 
-	assets := &[]dtos.GetAllAssetsRequest{}
-	err = json.Unmarshal([]byte(assetsString), assets)
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
+	assert.Nil(t, err)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, response.Items)
+	assert.Equal(t, len(response.Items), 0)
+}
+
+func Test_GivenInvalidTimeFilterField_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	filter := &dtos.Filter{
+		TimeFilter: dtos.TimestampFilter{
+			Field: "created",
+			Min:   normalTimestamp,
+			Max:   normalTimestamp.Add(time.Minute * 20),
+		},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the time filter field created is not valid")
+}
+
+func Test_GivenRecordedTimeFilter_whenGetAllAssets_thenQueryRecordedAt(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	filter := &dtos.Filter{
+		TimeFilter: dtos.TimestampFilter{
+			Field: "recorded",
+			Min:   normalTimestamp,
+			Max:   normalTimestamp.Add(time.Minute * 20),
+		},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	minimumEncoded, _ := json.Marshal(filter.TimeFilter.Min)
+	maximumEncoded, _ := json.Marshal(filter.TimeFilter.Max)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"recorded_at":{"$gte":` + string(minimumEncoded) + `,` + `"$lte":` + string(maximumEncoded) + `}}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 0,
+		Bookmark:            "",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil).Times(1)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Close().Return(nil).Times(1)
+
+	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Nil(t, err)
+
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
+	assert.Nil(t, err)
+	assert.Equal(t, len(response.Items), 0)
+}
+
+func Test_GivenFullPage_whenGetAllAssets_thenReturnPageMetadata(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 1,
+		Bookmark:            "firstBookmark",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(1)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: []byte(`{"id":"some_id"}`)}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", "{}")
+	assert.Nil(t, err)
+
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(response.Items))
+	assert.Equal(t, 0, response.Page)
+	assert.Equal(t, 1, response.Size)
+	assert.Equal(t, true, response.HasMore)
+	assert.Equal(t, "firstBookmark", response.Bookmark)
+	assert.Nil(t, response.Total)
+}
+
+func Test_GivenIncludeTotal_whenGetAllAssets_thenReturnTotal(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+	mockedCountIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	filter := &dtos.Filter{
+		IncludeTotal: true,
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 1,
+		Bookmark:            "firstBookmark",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(1)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: []byte(`{"id":"some_id"}`)}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	mockedChaincodeStub.EXPECT().GetQueryResult(expectedQuery).Return(mockedCountIterator, nil)
+	mockedCountIterator.EXPECT().HasNext().Return(true).Times(1)
+	mockedCountIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedCountIterator.EXPECT().Next().Return(&queryresult.KV{}, nil)
+	mockedCountIterator.EXPECT().Close().Return(nil)
+
+	assetsString, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Nil(t, err)
+
+	response := &dtos.GetAllAssetsResponse{}
+	err = json.Unmarshal([]byte(assetsString), response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(response.Items))
+	assert.Equal(t, false, response.HasMore)
+	assert.NotNil(t, response.Total)
+	assert.Equal(t, 1, *response.Total)
+}
+
+func Test_GivenErrorCounting_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 0,
+		Bookmark:            "",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Close().Return(nil)
+	mockedChaincodeStub.EXPECT().GetQueryResult(expectedQuery).Return(nil, fmt.Errorf("some exception"))
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "1", `{"include_total":true}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error counting the assets")
+}
+
+func Test_GivenSortByInvalidField_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	filter := &dtos.Filter{
+		Sort: []dtos.SortField{{Field: "description"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the field description can't be used to sort")
+}
+
+func Test_GivenSortWithInvalidDirection_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	filter := &dtos.Filter{
+		Sort: []dtos.SortField{{Field: "id", Direction: "up"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the sort direction up is not valid")
+}
+
+func Test_GivenSortWithMixedDirections_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	filter := &dtos.Filter{
+		Sort: []dtos.SortField{{Field: "type_form", Direction: "asc"}, {Field: "timestamp", Direction: "desc"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "all the sort fields must have the same direction")
+}
+
+func Test_GivenRepeatedSortField_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	filter := &dtos.Filter{
+		Sort: []dtos.SortField{{Field: "id"}, {Field: "id"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
 
+	result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the field id is repeated in the sort")
+}
+
+func Test_GivenSortWithoutIndex_whenGetAllAssets_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+
+	sorts := map[string][]dtos.SortField{
+		"timestamp, type_form": {{Field: "timestamp"}, {Field: "type_form"}},
+		"id, timestamp":        {{Field: "id"}, {Field: "timestamp"}},
+		"type_form, insertion_type, timestamp": {
+			{Field: "type_form"}, {Field: "insertion_type"}, {Field: "timestamp"},
+		},
+	}
+
+	for fields, sort := range sorts {
+		mockCallerWithRole(controller, mockedTransaction, "auditor")
+		encodedFilter, err := json.Marshal(&dtos.Filter{Sort: sort})
+		assert.Nil(t, err)
+
+		result, err := smartContract.GetAllAssets(mockedTransaction, "0", "10", string(encodedFilter))
+		assert.Equal(t, "", result)
+		assert.NotNil(t, err)
+		assert.Equal(t, err.Error(), "there is no index to sort by "+fields)
+	}
+}
+
+func Test_GivenSortByInsertionTypeAndTimestamp_whenGetAllAssets_thenQueryWithSort(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	filter := &dtos.Filter{
+		InsertionTypes: []string{normalInsertionType},
+		Sort:           []dtos.SortField{{Field: "insertion_type"}, {Field: "timestamp"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
 	assert.Nil(t, err)
-	assert.NotNil(t, assets)
-	assert.Equal(t, len(*assets), 0)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"insertion_type":{"$in":["` + utils.RemoveStringSpaces(normalInsertionType) + `"]}},"sort":[{"insertion_type":"asc"},{"timestamp":"asc"}]}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 0,
+		Bookmark:            "",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Nil(t, err)
+}
+
+func Test_GivenSortWithoutSelectorField_whenGetAllAssets_thenQueryWithSortAndSelector(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	filter := &dtos.Filter{
+		Sort: []dtos.SortField{{Field: "type_form", Direction: "DESC"}, {Field: "timestamp", Direction: "desc"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"type_form":{"$gt":null}},"sort":[{"type_form":"desc"},{"timestamp":"desc"}]}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 0,
+		Bookmark:            "",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Nil(t, err)
+}
+
+func Test_GivenSortByFilteredField_whenGetAllAssets_thenQueryWithSort(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	filter := &dtos.Filter{
+		Ids:  []string{normalId},
+		Sort: []dtos.SortField{{Field: "id"}},
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	expectedQuery := `{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}],"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]}},"sort":[{"id":"asc"}]}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 0,
+		Bookmark:            "",
+	}
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(false).Times(1)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.Nil(t, err)
+}
+
+func Test_GivenIncludeDeleted_whenGetAllAssets_thenQueryDeletedAssets(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	filter := &dtos.Filter{
+		Ids:            []string{normalId},
+		IncludeDeleted: true,
+	}
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	expectedQuery := `{"selector":{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}],"id":{"$in":["` + utils.RemoveStringSpaces(normalId) + `"]}}}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(expectedQuery, int32(1), "").Return(nil, nil, fmt.Errorf("some exception"))
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error querying the ledger")
 }
@@ -0,0 +1,129 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func historyAsOf(t *testing.T) []*queryresult.KeyModification {
+	cleanId := utils.RemoveStringSpaces(normalId)
+	created := &dtos.AssetRequest{Id: cleanId, Hash: "hash_1"}
+	patched := &dtos.AssetRequest{Id: cleanId, Hash: "hash_2"}
+
+	return []*queryresult.KeyModification{
+		{TxId: "tx_3", Timestamp: timestamppb.New(normalTxTime.Add(2 * time.Hour)), IsDelete: true},
+		{TxId: "tx_2", Timestamp: timestamppb.New(normalTxTime.Add(time.Hour)), Value: encodeHistoryValue(t, patched)},
+		{TxId: "tx_1", Timestamp: normalTxTimestamp, Value: encodeHistoryValue(t, created)},
+	}
+}
+
+// getAssetAsOf mocks the first read modifications, exhausted when the iterator reaches the end
+func getAssetAsOf(t *testing.T, history []*queryresult.KeyModification, read int, exhausted bool, asOf time.Time) *dtos.AssetAsOfResponse {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIterator, nil)
+	for _, item := range history[:read] {
+		mockedHistoryIterator.EXPECT().HasNext().Return(true)
+		mockedHistoryIterator.EXPECT().Next().Return(item, nil)
+	}
+	if exhausted {
+		mockedHistoryIterator.EXPECT().HasNext().Return(false)
+	}
+	mockedHistoryIterator.EXPECT().Close().Return(nil)
+
+	result, err := smartContract.GetAssetAsOf(mockedTransaction, normalId, asOf.Format(time.RFC3339))
+	assert.Nil(t, err)
+
+	response := &dtos.AssetAsOfResponse{}
+	err = json.Unmarshal([]byte(result), response)
+	assert.Nil(t, err)
+	return response
+}
+
+func Test_givenInvalidId_whenGetAssetAsOf_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAssetAsOf(mockedTransaction, "  ", "2025-04-05T12:30:45Z")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+}
+
+func Test_givenInvalidTime_whenGetAssetAsOf_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAssetAsOf(mockedTransaction, normalId, "2025-04-05")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the time 2025-04-05 is not valid")
+}
+
+func Test_givenNoHistory_whenGetAssetAsOf_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIterator, nil)
+	mockHistory(mockedHistoryIterator, nil)
+
+	result, err := smartContract.GetAssetAsOf(mockedTransaction, normalId, "2025-04-05T12:30:45Z")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset doesn't exist")
+}
+
+func Test_givenTimeBeforeCreation_whenGetAssetAsOf_thenNotCreated(t *testing.T) {
+	history := historyAsOf(t)
+
+	response := getAssetAsOf(t, history, len(history), true, normalTxTime.Add(-time.Minute))
+	assert.Equal(t, "not_created", response.Status)
+	assert.Equal(t, normalTxTime.Add(-time.Minute), response.AsOf)
+	assert.Nil(t, response.Version)
+}
+
+func Test_givenTimeOfCreation_whenGetAssetAsOf_thenReturnFirstVersion(t *testing.T) {
+	history := historyAsOf(t)
+
+	response := getAssetAsOf(t, history, len(history), false, normalTxTime)
+	assert.Equal(t, "existing", response.Status)
+	assert.Equal(t, "tx_1", response.Version.TxId)
+	assert.Equal(t, "hash_1", response.Version.Asset.Hash)
+}
+
+func Test_givenTimeBetweenVersions_whenGetAssetAsOf_thenReturnCurrentVersion(t *testing.T) {
+	history := historyAsOf(t)
+
+	response := getAssetAsOf(t, history, 2, false, normalTxTime.Add(90*time.Minute))
+	assert.Equal(t, "existing", response.Status)
+	assert.Equal(t, "tx_2", response.Version.TxId)
+	assert.Equal(t, "hash_2", response.Version.Asset.Hash)
+}
+
+func Test_givenTimeAfterDelete_whenGetAssetAsOf_thenDeleted(t *testing.T) {
+	history := historyAsOf(t)
+
+	response := getAssetAsOf(t, history, 1, false, normalTxTime.Add(3*time.Hour))
+	assert.Equal(t, "deleted", response.Status)
+	assert.Equal(t, "tx_3", response.Version.TxId)
+	assert.Nil(t, response.Version.Asset)
+}
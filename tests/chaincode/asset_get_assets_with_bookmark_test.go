@@ -0,0 +1,92 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenInvalidSize_whenGetAssetsWithBookmark_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAssetsWithBookmark(mockedTransaction, "", "0", "{}")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "size is not valid")
+}
+
+func Test_GivenInvalidFilter_whenGetAssetsWithBookmark_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetAssetsWithBookmark(mockedTransaction, "", "10", "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error decoding filter")
+}
+
+func Test_GivenErrorQuerying_whenGetAssetsWithBookmark_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(`{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`, int32(10), "someBookmark").Return(nil, nil, fmt.Errorf("some exception"))
+
+	result, err := smartContract.GetAssetsWithBookmark(mockedTransaction, "someBookmark", "10", "{}")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error querying the ledger")
+}
+
+func Test_GivenBookmark_whenGetAssetsWithBookmark_thenQueryOnlyOnePage(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	asset := &dtos.GetAllAssetsRequest{
+		Id:            normalId,
+		TypeForm:      normalTypeForm,
+		Description:   normalDescription,
+		Timestamp:     normalTimestamp,
+		InsertionType: normalInsertionType,
+		Hash:          normalHash,
+	}
+	encodedAsset, err := json.Marshal(asset)
+	assert.Nil(t, err)
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: 2,
+		Bookmark:            "nextBookmark",
+	}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(`{"selector":{"$and":[{"$or":[{"doc_type":"form"},{"doc_type":{"$exists":false}}]},{"$or":[{"deleted":false},{"deleted":{"$exists":false}}]}]}}`, int32(2), "someBookmark").Return(mockedIterator, metadata, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(2)
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: encodedAsset}, nil).Times(2)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	resultString, err := smartContract.GetAssetsWithBookmark(mockedTransaction, "someBookmark", "2", "{}")
+	assert.Nil(t, err)
+
+	result := &dtos.GetAssetsWithBookmarkResponse{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(result.Items))
+	assert.Equal(t, "nextBookmark", result.Bookmark)
+	assert.Equal(t, int32(2), result.FetchedRecordsCount)
+	assert.Equal(t, asset.Id, result.Items[0].Id)
+}
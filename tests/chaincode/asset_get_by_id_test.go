@@ -14,15 +14,14 @@ import (
 
 var emptyString = " "
 var normalId = "some _id"
-var normalTypeForm = "some_type_form"
 var normalDescription = "some_description"
 var normalTimestamp = time.Now()
 var normalInsertionType = "some_insertion_type"
-var normalHash = "some_hash"
 
 func Test_given_invalid_id_string_when_GetAssetById_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedStub := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedStub, "auditor")
 
 	asset, err := smartContract.GetAssetById(mockedStub, emptyString)
 	assert.Equal(t, "", asset)
@@ -33,6 +32,7 @@ func Test_given_invalid_id_string_when_GetAssetById_thenReturnException(t *testi
 func Test_given_invalid_id_whenGetAssetById_thenReturnException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
@@ -47,6 +47,7 @@ func Test_given_invalid_id_whenGetAssetById_thenReturnException(t *testing.T) {
 func Test_given_valid_id_whenGetAssetById_thenReturnTrueObject(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
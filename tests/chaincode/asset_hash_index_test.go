@@ -0,0 +1,365 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func mockHashLookup(controller *gomock.Controller, mockedChaincodeStub *mocks.MockChaincodeStubInterface, hash string, ids ...string) {
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+	mockedChaincodeStub.EXPECT().GetStateByPartialCompositeKey("hash~id", []string{hash}).Return(mockedIterator, nil)
+	for _, id := range ids {
+		mockedIterator.EXPECT().HasNext().Return(true)
+		mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: hashIndexKey(hash, id), Value: []byte{0x00}}, nil)
+		mockedChaincodeStub.EXPECT().SplitCompositeKey(hashIndexKey(hash, id)).Return("hash~id", []string{hash, id}, nil)
+	}
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Close().Return(nil)
+}
+
+func createIndexedAsset(t *testing.T, policy string, otherDeleted bool) (*mocks.MockChaincodeStubInterface, func() (string, error)) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, DuplicateHashPolicy: policy})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_2")
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(encodeStoredAsset(t, "form_2", withDeleted(otherDeleted)), nil)
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	return mockedChaincodeStub, func() (string, error) {
+		return smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	}
+}
+
+func Test_givenRejectPolicyAndUsedHash_whenCreateAsset_thenException(t *testing.T) {
+	_, createAsset := createIndexedAsset(t, "reject", false)
+
+	result, err := createAsset()
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the hash is already used by form_2", err.Error())
+}
+
+func Test_givenRejectPolicyAndHashOfDeletedAsset_whenCreateAsset_thenCreateAndIndex(t *testing.T) {
+	mockedChaincodeStub, createAsset := createIndexedAsset(t, "reject", true)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_1"), []byte{0x00}).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormCreated", event)
+
+	_, err := createAsset()
+	assert.Nil(t, err)
+	assert.Nil(t, event.DuplicateIds)
+}
+
+func Test_givenWarnPolicyAndUsedHash_whenCreateAsset_thenCreateAndReportTheDuplicates(t *testing.T) {
+	mockedChaincodeStub, createAsset := createIndexedAsset(t, "warn", false)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_1"), []byte{0x00}).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormCreated", event)
+
+	_, err := createAsset()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"form_2"}, event.DuplicateIds)
+}
+
+func Test_givenRejectPolicyAndRepeatedHash_whenCreateAssets_thenRejectTheBatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, DuplicateHashPolicy: "reject"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockHashLookup(controller, mockedChaincodeStub, normalHash)
+
+	result, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t, newBulkRequest("form_1"), newBulkRequest("form_2")))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+
+	itemErrors := []*dtos.BatchItemError{}
+	err = json.Unmarshal([]byte(strings.TrimPrefix(err.Error(), "the batch was rejected ")), &itemErrors)
+	assert.Nil(t, err)
+	assert.Equal(t, []*dtos.BatchItemError{{Index: 1, Id: "form_2", Error: "the hash is repeated in the item 0"}}, itemErrors)
+}
+
+func Test_givenWarnPolicyAndUsedHash_whenCreateAssets_thenReportTheDuplicatesInTheEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, DuplicateHashPolicy: "warn"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_3")
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_3")
+	mockedChaincodeStub.EXPECT().GetState("form_3").Return(encodeStoredAsset(t, "form_3"), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil).Times(2)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().PutState("form_2", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+
+	event := &dtos.AssetBatchEvent{}
+	mockedChaincodeStub.EXPECT().SetEvent("FormsCreated", gomock.Any()).DoAndReturn(func(name string, payload []byte) error {
+		return json.Unmarshal(payload, event)
+	})
+
+	_, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t, newBulkRequest("form_1"), newBulkRequest("form_2")))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"form_1": {"form_3"}, "form_2": {"form_3", "form_1"}}, event.Duplicates)
+}
+
+func Test_givenRejectPolicyAndUsedHash_whenRestoreAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DuplicateHashPolicy: "reject"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withDeleted(true)), nil).Times(2)
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_1", "form_2")
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(encodeStoredAsset(t, "form_2"), nil)
+
+	result, err := smartContract.RestoreAsset(mockedTransaction, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the hash is already used by form_2", err.Error())
+}
+
+func Test_givenWarnPolicyAndUsedHash_whenRestoreAsset_thenRestoreAndReportTheDuplicates(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DuplicateHashPolicy: "warn"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withDeleted(true)), nil).Times(2)
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_1", "form_2")
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(encodeStoredAsset(t, "form_2"), nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormRestored", event)
+
+	_, err := smartContract.RestoreAsset(mockedTransaction, "form_1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"form_2"}, event.DuplicateIds)
+}
+
+func Test_givenRejectPolicyAndUsedHash_whenPatchAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DuplicateHashPolicy: "reject"})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockHashLookup(controller, mockedChaincodeStub, newHash, "form_2")
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(encodeStoredAsset(t, "form_2"), nil)
+
+	encodedPatch, err := json.Marshal(&dtos.PutAssetRequest{Hash: newHash})
+	assert.Nil(t, err)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, string(encodedPatch), "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the hash is already used by form_2", err.Error())
+}
+
+func Test_givenChangedHash_whenPatchAsset_thenMoveTheIndex(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, "form_1")).Return(nil)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(newHash, "form_1"), []byte{0x00}).Return(nil)
+	captureEvent(mockedChaincodeStub, "FormPatched", &dtos.AssetEvent{})
+
+	encodedPatch, err := json.Marshal(&dtos.PutAssetRequest{Hash: newHash})
+	assert.Nil(t, err)
+
+	_, err = smartContract.PatchAsset(mockedTransaction, string(encodedPatch), "form_1")
+	assert.Nil(t, err)
+}
+
+func Test_givenRejectPolicyAndHash_whenPatchAssets_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DuplicateHashPolicy: "reject"})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.PatchAssets(mockedTransaction, `{"ids":["form_1","form_2"]}`, `{"hash":"`+newHash+`"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the hash can't be patched in several assets when the duplicates are rejected", err.Error())
+}
+
+func Test_givenLegacyHash_whenFindAssetsByHash_thenLookItUpAsGiven(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashLookup(controller, mockedChaincodeStub, "legacy-hash", "form_1")
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil)
+
+	resultString, err := smartContract.FindAssetsByHash(mockedTransaction, " legacy-hash", "")
+	assert.Nil(t, err)
+
+	result := []*dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "form_1", result[0].Id)
+}
+
+func Test_givenIndexedHash_whenFindAssetsByHash_thenReturnTheAssets(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashLookup(controller, mockedChaincodeStub, normalHash, "form_1", "form_2")
+	mockHashLookup(controller, mockedChaincodeStub, strings.ToUpper(normalHash), "form_2", "form_3")
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(encodeStoredAsset(t, "form_2", withDeleted(true)), nil)
+	mockedChaincodeStub.EXPECT().GetState("form_3").Return(encodeStoredAsset(t, "form_3"), nil)
+
+	resultString, err := smartContract.FindAssetsByHash(mockedTransaction, strings.ToUpper(normalHash), "SHA-256")
+	assert.Nil(t, err)
+
+	result := []*dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, "form_1", result[0].Id)
+	assert.Equal(t, true, result[1].Deleted)
+	assert.Equal(t, "form_3", result[2].Id)
+}
+
+func Test_givenSubmitter_whenRebuildHashIndex_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.RebuildHashIndex(mockedTransaction, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "the role submitter is not allowed to perform this operation", err.Error())
+}
+
+func Test_givenMoreAssetsThanTheBatchSize_whenRebuildHashIndex_thenIndexAndReturnTheNextKey(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{MaxBatchSize: 2})
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetStateByRange("form_1", "").Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(4)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_1", Value: encodeStoredAsset(t, "form_1")}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_2", Value: []byte("not an asset")}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_3", Value: encodeStoredAsset(t, "form_3", withDeleted(true))}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_4", Value: encodeStoredAsset(t, "form_4")}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_1"), []byte{0x00}).Return(nil)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_3"), []byte{0x00}).Return(nil)
+
+	result, err := smartContract.RebuildHashIndex(mockedTransaction, "form_1")
+	assert.Nil(t, err)
+	assert.Equal(t, "form_4", result)
+}
+
+func Test_givenLastAssets_whenRebuildHashIndex_thenReturnEmptyKey(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetStateByRange("", "").Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true)
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_1", Value: encodeStoredAsset(t, "form_1")}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_1"), []byte{0x00}).Return(nil)
+
+	result, err := smartContract.RebuildHashIndex(mockedTransaction, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", result)
+}
+
+func Test_givenLegacyAssetWithoutDocType_whenRebuildHashIndex_thenIndexIt(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	legacyAsset := `{"id":"form_1","type_form":"` + normalTypeForm + `","hash":"` + normalHash + `"}`
+	otherDocument := `{"id":"form_9","hash":"` + normalHash + `"}`
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetStateByRange("", "").Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(2)
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "form_1", Value: []byte(legacyAsset)}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Key: "other_key", Value: []byte(otherDocument)}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKey(normalHash, "form_1"), []byte{0x00}).Return(nil)
+
+	result, err := smartContract.RebuildHashIndex(mockedTransaction, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", result)
+}
@@ -0,0 +1,163 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+	"strings"
+	"testing"
+)
+
+var hashedDocument = []byte("the scanned form")
+
+func createWithHash(t *testing.T, algorithm string, hash string) (*dtos.AssetRequest, error) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	request := newBulkRequest("form_1")
+	request.HashAlgorithm = algorithm
+	request.Hash = hash
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId).AnyTimes()
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil).AnyTimes()
+
+	resultString, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), asset)
+	assert.Nil(t, err)
+	return asset, nil
+}
+
+func Test_givenMalformedHash_whenCreateAsset_thenException(t *testing.T) {
+	_, err := createWithHash(t, "", "abc")
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the hash is not a valid sha256 digest")
+}
+
+func Test_givenHashOfAnotherLength_whenCreateAsset_thenException(t *testing.T) {
+	_, err := createWithHash(t, "SHA-384", normalHash)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the hash is not a valid sha384 digest")
+}
+
+func Test_givenUnsupportedAlgorithm_whenCreateAsset_thenException(t *testing.T) {
+	_, err := createWithHash(t, "md5", normalHash)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the hash algorithm md5 is not supported")
+}
+
+func Test_givenEverySupportedAlgorithm_whenCreateAsset_thenStoreLowercaseHex(t *testing.T) {
+	sha256Digest := sha256.Sum256(hashedDocument)
+	sha384Digest := sha512.Sum384(hashedDocument)
+	sha3Digest := sha3.Sum256(hashedDocument)
+	blake2bDigest := blake2b.Sum512(hashedDocument)
+	blake2b256Digest := blake2b.Sum256(hashedDocument)
+
+	cases := []struct {
+		algorithm       string
+		hash            string
+		storedAlgorithm string
+		storedHash      string
+	}{
+		{"", strings.ToUpper(hex.EncodeToString(sha256Digest[:])), "sha256", hex.EncodeToString(sha256Digest[:])},
+		{"SHA-384", base64.StdEncoding.EncodeToString(sha384Digest[:]), "sha384", hex.EncodeToString(sha384Digest[:])},
+		{"sha3_256", base64.RawURLEncoding.EncodeToString(sha3Digest[:]), "sha3-256", hex.EncodeToString(sha3Digest[:])},
+		{"BLAKE2b", hex.EncodeToString(blake2bDigest[:]), "blake2b-512", hex.EncodeToString(blake2bDigest[:])},
+		{"blake2b-256", base64.StdEncoding.EncodeToString(blake2b256Digest[:]), "blake2b-256", hex.EncodeToString(blake2b256Digest[:])},
+	}
+
+	for _, testCase := range cases {
+		asset, err := createWithHash(t, testCase.algorithm, testCase.hash)
+		assert.Nil(t, err, testCase.algorithm)
+		assert.Equal(t, testCase.storedAlgorithm, asset.HashAlgorithm, testCase.algorithm)
+		assert.Equal(t, testCase.storedHash, asset.Hash, testCase.algorithm)
+	}
+}
+
+func Test_givenOnlyAnotherAlgorithm_whenPatchAsset_thenValidateTheStoredHash(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+
+	result, err := smartContract.PatchAsset(mockedTransaction, `{"hash_algorithm":"sha384"}`, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the hash is not a valid sha384 digest")
+}
+
+func Test_givenAssetHashedWithSha384_whenVerifyDocumentHash_thenUseItsAlgorithm(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	sha384Digest := sha512.Sum384(hashedDocument)
+	storedAsset := encodeHistoryValue(t, &dtos.AssetRequest{
+		Id:            "form_1",
+		Hash:          hex.EncodeToString(sha384Digest[:]),
+		HashAlgorithm: "sha384",
+	})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(storedAsset, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"document": hashedDocument}, nil)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey("form_1").Return(mockedHistoryIterator, nil)
+	mockHistory(mockedHistoryIterator, []*queryresult.KeyModification{
+		{TxId: "tx_1", Timestamp: normalTxTimestamp, Value: storedAsset},
+	})
+
+	resultString, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", "")
+	assert.Nil(t, err)
+
+	result := &dtos.DocumentVerification{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.True(t, result.Match)
+	assert.Equal(t, "sha384", result.Algorithm)
+}
+
+func Test_givenMalformedDigest_whenVerifyDocumentHash_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil)
+
+	result, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", "abc")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the digest is not a valid sha256 digest")
+}
@@ -12,13 +12,18 @@ import (
 	"testing"
 )
 
-func Test_given_invalidId_thenException(t *testing.T) {
+func Test_given_idWithoutHistory_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 
+	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
-	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{}, nil)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false)
+	mockedHistoryIteratorMock.EXPECT().Close().Return(nil)
 
 	result, err := smartContract.GetHistoryAssetById(mockedTransaction, normalId)
 	assert.NotNil(t, err)
@@ -29,12 +34,10 @@ func Test_given_invalidId_thenException(t *testing.T) {
 func Test_given_validIdAndOneItemHistory_thenReturnArrayLength1(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
-	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{123}, nil)
-
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
 
@@ -58,6 +61,15 @@ func Test_given_validIdAndOneItemHistory_thenReturnArrayLength1(t *testing.T) {
 		Value:     valueEncoded,
 		IsDelete:  false,
 	}
+	expectedAsset := &dtos.AssetRequest{}
+	err = json.Unmarshal(valueEncoded, expectedAsset)
+	assert.Nil(t, err)
+	expectedEntry := &dtos.AssetHistoryEntry{
+		TxId:      item.TxId,
+		Timestamp: item.Timestamp.AsTime().UTC(),
+		IsDelete:  false,
+		Asset:     expectedAsset,
+	}
 	mockedHistoryIteratorMock.EXPECT().Next().Return(item, nil).Times(1)
 	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false).Times(1)
 	mockedHistoryIteratorMock.EXPECT().Close().Return(nil).Times(1)
@@ -66,25 +78,23 @@ func Test_given_validIdAndOneItemHistory_thenReturnArrayLength1(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotEqual(t, "", result)
 
-	decodedResult := &[]*queryresult.KeyModification{}
+	decodedResult := &[]*dtos.AssetHistoryEntry{}
 	err = json.Unmarshal([]byte(result), decodedResult)
 	assert.Nil(t, err)
 
 	assert.Equal(t, len((*decodedResult)), 1)
 
 	singleItem := (*decodedResult)[0]
-	assert.Equal(t, singleItem, item)
+	assert.Equal(t, singleItem, expectedEntry)
 }
 
 func Test_given_validIdAndTwoItemHistory_thenReturnArrayLength2(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
 	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
 	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
-	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{123}, nil)
-
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
 	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
 
@@ -107,6 +117,15 @@ func Test_given_validIdAndTwoItemHistory_thenReturnArrayLength2(t *testing.T) {
 		Value:     valueEncoded,
 		IsDelete:  false,
 	}
+	expectedAsset := &dtos.AssetRequest{}
+	err = json.Unmarshal(valueEncoded, expectedAsset)
+	assert.Nil(t, err)
+	expectedEntry := &dtos.AssetHistoryEntry{
+		TxId:      item.TxId,
+		Timestamp: item.Timestamp.AsTime().UTC(),
+		IsDelete:  false,
+		Asset:     expectedAsset,
+	}
 	mockedHistoryIteratorMock.EXPECT().Next().Return(item, nil).Times(2)
 	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false).Times(1)
 	mockedHistoryIteratorMock.EXPECT().Close().Return(nil).Times(1)
@@ -115,15 +134,124 @@ func Test_given_validIdAndTwoItemHistory_thenReturnArrayLength2(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotEqual(t, "", result)
 
-	decodedResult := &[]*queryresult.KeyModification{}
+	decodedResult := &[]*dtos.AssetHistoryEntry{}
 	err = json.Unmarshal([]byte(result), decodedResult)
 	assert.Nil(t, err)
 
 	assert.Equal(t, len((*decodedResult)), 2)
 
 	singleItem := (*decodedResult)[0]
-	assert.Equal(t, singleItem, item)
+	assert.Equal(t, singleItem, expectedEntry)
 
 	otherItem := (*decodedResult)[1]
-	assert.Equal(t, otherItem, item)
+	assert.Equal(t, otherItem, expectedEntry)
+}
+
+func Test_given_invalidId_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetHistoryAssetById(mockedTransaction, "   ")
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+	assert.Equal(t, "", result)
+}
+
+func Test_given_deletedAsset_thenReturnHistoryWithDeletes(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
+
+	created, err := json.Marshal(&dtos.AssetRequest{Id: utils.RemoveStringSpaces(normalId)})
+	assert.Nil(t, err)
+	softDeleted, err := json.Marshal(&dtos.AssetRequest{Id: utils.RemoveStringSpaces(normalId), Deleted: true})
+	assert.Nil(t, err)
+
+	history := []*queryresult.KeyModification{
+		{TxId: "tx_1", Timestamp: timestamppb.New(normalTimestamp), Value: created},
+		{TxId: "tx_2", Timestamp: normalTxTimestamp, Value: softDeleted},
+		{TxId: "tx_3", Timestamp: normalTxTimestamp, IsDelete: true},
+	}
+	for _, item := range history {
+		mockedHistoryIteratorMock.EXPECT().HasNext().Return(true)
+		mockedHistoryIteratorMock.EXPECT().Next().Return(item, nil)
+	}
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false)
+	mockedHistoryIteratorMock.EXPECT().Close().Return(nil)
+
+	result, err := smartContract.GetHistoryAssetById(mockedTransaction, normalId)
+	assert.Nil(t, err)
+
+	decodedResult := []*dtos.AssetHistoryEntry{}
+	err = json.Unmarshal([]byte(result), &decodedResult)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(decodedResult))
+	assert.False(t, decodedResult[0].IsDelete)
+	assert.NotNil(t, decodedResult[0].Asset)
+	assert.True(t, decodedResult[1].IsDelete)
+	assert.Equal(t, normalTxTime, decodedResult[1].Timestamp)
+	assert.True(t, decodedResult[1].Asset.Deleted)
+	assert.True(t, decodedResult[2].IsDelete)
+	assert.Equal(t, normalTxTime, decodedResult[2].Timestamp)
+	assert.Nil(t, decodedResult[2].Asset)
+}
+
+func Test_given_historyEntry_thenReturnDecodedJson(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
+
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(true)
+	mockedHistoryIteratorMock.EXPECT().Next().Return(&queryresult.KeyModification{
+		TxId:      normalTxId,
+		Timestamp: normalTxTimestamp,
+		Value:     []byte(`{"id":"form_1","hash":"some_hash"}`),
+	}, nil)
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false)
+	mockedHistoryIteratorMock.EXPECT().Close().Return(nil)
+
+	result, err := smartContract.GetHistoryAssetById(mockedTransaction, normalId)
+	assert.Nil(t, err)
+
+	decodedResult := []map[string]interface{}{}
+	err = json.Unmarshal([]byte(result), &decodedResult)
+	assert.Nil(t, err)
+
+	assert.Equal(t, normalTxId, decodedResult[0]["tx_id"])
+	assert.Equal(t, "2025-04-05T12:30:45Z", decodedResult[0]["timestamp"])
+	assert.Equal(t, false, decodedResult[0]["is_delete"])
+	assert.Equal(t, "some_hash", decodedResult[0]["asset"].(map[string]interface{})["hash"])
+}
+
+func Test_given_historyEntryWithInvalidValue_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIteratorMock := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(1)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIteratorMock, nil)
+
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(true)
+	mockedHistoryIteratorMock.EXPECT().Next().Return(&queryresult.KeyModification{TxId: normalTxId, Value: []byte{1, 0, 1}}, nil)
+	mockedHistoryIteratorMock.EXPECT().HasNext().Return(false)
+	mockedHistoryIteratorMock.EXPECT().Close().Return(nil)
+
+	result, err := smartContract.GetHistoryAssetById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error decoding the history entry")
 }
@@ -0,0 +1,155 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+// historyWithFilter has one modification per hour from tx_1 to tx_5, newest first as fabric returns it
+func historyWithFilter(t *testing.T) []*queryresult.KeyModification {
+	history := []*queryresult.KeyModification{}
+	for i := 5; i >= 1; i-- {
+		asset := &dtos.AssetRequest{Id: utils.RemoveStringSpaces(normalId), Hash: fmt.Sprintf("hash_%d", i)}
+		history = append(history, &queryresult.KeyModification{
+			TxId:      fmt.Sprintf("tx_%d", i),
+			Timestamp: timestamppb.New(normalTxTime.Add(time.Duration(i-1) * time.Hour)),
+			Value:     encodeHistoryValue(t, asset),
+		})
+	}
+	return history
+}
+
+func getHistoryWithFilter(t *testing.T, read int, exhausted bool, offset string, limit string, filter *dtos.HistoryFilter) *dtos.AssetHistoryPage {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey(utils.RemoveStringSpaces(normalId)).Return(mockedHistoryIterator, nil)
+	for _, item := range historyWithFilter(t)[:read] {
+		mockedHistoryIterator.EXPECT().HasNext().Return(true)
+		mockedHistoryIterator.EXPECT().Next().Return(item, nil)
+	}
+	if exhausted {
+		mockedHistoryIterator.EXPECT().HasNext().Return(false)
+	}
+	mockedHistoryIterator.EXPECT().Close().Return(nil)
+
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, normalId, offset, limit, string(encodedFilter))
+	assert.Nil(t, err)
+
+	page := &dtos.AssetHistoryPage{}
+	err = json.Unmarshal([]byte(result), page)
+	assert.Nil(t, err)
+	return page
+}
+
+func getTxIds(page *dtos.AssetHistoryPage) []string {
+	txIds := []string{}
+	for _, item := range page.Items {
+		txIds = append(txIds, item.TxId)
+	}
+	return txIds
+}
+
+func Test_givenInvalidId_whenGetHistoryAssetByIdWithFilter_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, " ", "0", "10", "{}")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+}
+
+func Test_givenInvalidLimit_whenGetHistoryAssetByIdWithFilter_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, normalId, "0", "0", "{}")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "offset and limit are not consistent")
+}
+
+func Test_givenNegativeOffset_whenGetHistoryAssetByIdWithFilter_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, normalId, "-1", "10", "{}")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "offset and limit are not consistent")
+}
+
+func Test_givenInvalidFilter_whenGetHistoryAssetByIdWithFilter_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, normalId, "0", "10", "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error decoding filter")
+}
+
+func Test_givenMinAfterMax_whenGetHistoryAssetByIdWithFilter_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+
+	filter, err := json.Marshal(&dtos.HistoryFilter{Min: normalTxTime, Max: normalTxTime.Add(-time.Hour)})
+	assert.Nil(t, err)
+
+	result, err := smartContract.GetHistoryAssetByIdWithFilter(mockedTransaction, normalId, "0", "10", string(filter))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "minimum interval should not be after the maximum")
+}
+
+func Test_givenNoFilter_whenGetHistoryAssetByIdWithFilter_thenReturnWholeHistory(t *testing.T) {
+	page := getHistoryWithFilter(t, 5, true, "0", "10", &dtos.HistoryFilter{})
+
+	assert.Equal(t, []string{"tx_5", "tx_4", "tx_3", "tx_2", "tx_1"}, getTxIds(page))
+	assert.Equal(t, 0, page.Offset)
+	assert.Equal(t, 10, page.Limit)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, "hash_5", page.Items[0].Asset.Hash)
+}
+
+func Test_givenTimeWindow_whenGetHistoryAssetByIdWithFilter_thenStopAtTheFirstOlderModification(t *testing.T) {
+	filter := &dtos.HistoryFilter{Min: normalTxTime.Add(time.Hour), Max: normalTxTime.Add(3 * time.Hour)}
+
+	page := getHistoryWithFilter(t, 5, false, "0", "10", filter)
+
+	assert.Equal(t, []string{"tx_4", "tx_3", "tx_2"}, getTxIds(page))
+	assert.False(t, page.HasMore)
+}
+
+func Test_givenTimeWindowAndOffset_whenGetHistoryAssetByIdWithFilter_thenReturnOnePageAndHasMore(t *testing.T) {
+	filter := &dtos.HistoryFilter{Min: normalTxTime.Add(time.Hour), Max: normalTxTime.Add(3 * time.Hour)}
+
+	page := getHistoryWithFilter(t, 4, false, "1", "1", filter)
+
+	assert.Equal(t, []string{"tx_3"}, getTxIds(page))
+	assert.Equal(t, 1, page.Offset)
+	assert.Equal(t, 1, page.Limit)
+	assert.True(t, page.HasMore)
+}
@@ -0,0 +1,133 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var idempotencyKey = "request-1"
+var idempotencyRecordKey = "\x00idempotency\x00" + normalMspId + "\x00" + idempotencyKey + "\x00"
+
+func encodeIdempotentRequest(t *testing.T, hash string) string {
+	request := &dtos.PostAssetRequest{
+		Id:             normalIdCreation,
+		TypeForm:       normalTypeFormCreation,
+		Description:    normalDescriptionCreation,
+		Timestamp:      normalTxTime,
+		InsertionType:  normalInsertionTypeCreation,
+		Hash:           hash,
+		IdempotencyKey: " " + idempotencyKey,
+	}
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+	return string(encodedRequest)
+}
+
+// createWithIdempotencyKey creates the asset for the first time and returns the stored idempotency record
+func createWithIdempotencyKey(t *testing.T) []byte {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	record := []byte{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("idempotency", []string{normalMspId, idempotencyKey}).Return(idempotencyRecordKey, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(idempotencyRecordKey).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalIdCreation)).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalIdCreation), gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().PutState(idempotencyRecordKey, gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		record = value
+		return nil
+	})
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil)
+
+	_, err := smartContract.CreateAsset(mockedTransaction, encodeIdempotentRequest(t, normalHashCreation))
+	assert.Nil(t, err)
+	return record
+}
+
+func Test_givenIdempotencyKey_whenCreateAsset_thenStoreRecord(t *testing.T) {
+	record := &dtos.IdempotencyRecord{}
+	err := json.Unmarshal(createWithIdempotencyKey(t), record)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "idempotency", record.DocType)
+	assert.Equal(t, idempotencyKey, record.Key)
+	assert.Equal(t, 64, len(record.RequestHash))
+	assert.Equal(t, utils.RemoveStringSpaces(normalIdCreation), record.Asset.Id)
+	assert.Equal(t, 1, record.Asset.Version)
+}
+
+func Test_givenRetryWithSameRequest_whenCreateAsset_thenReturnOriginalAsset(t *testing.T) {
+	record := createWithIdempotencyKey(t)
+
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("idempotency", []string{normalMspId, idempotencyKey}).Return(idempotencyRecordKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(idempotencyRecordKey).Return(record, nil)
+
+	resultString, err := smartContract.CreateAsset(mockedTransaction, encodeIdempotentRequest(t, normalHashCreation))
+	assert.Nil(t, err)
+
+	result := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.RemoveStringSpaces(normalIdCreation), result.Id)
+	assert.Equal(t, utils.RemoveStringSpaces(normalHashCreation), result.Hash)
+	assert.Equal(t, normalOwner, result.Owner)
+}
+
+func Test_givenRetryWithDifferentRequest_whenCreateAsset_thenException(t *testing.T) {
+	record := createWithIdempotencyKey(t)
+
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil).AnyTimes()
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("idempotency", []string{normalMspId, idempotencyKey}).Return(idempotencyRecordKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(idempotencyRecordKey).Return(record, nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, encodeIdempotentRequest(t, newHash))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the idempotency key request-1 was already used with a different request")
+}
+
+func Test_givenErrorReadingRecord_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("idempotency", []string{normalMspId, idempotencyKey}).Return(idempotencyRecordKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(idempotencyRecordKey).Return(nil, fmt.Errorf("some exception"))
+
+	result, err := smartContract.CreateAsset(mockedTransaction, encodeIdempotentRequest(t, normalHashCreation))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error reading the idempotency record")
+}
@@ -13,6 +13,7 @@ import (
 func Test_givenInvalidId_whenPatchAsset_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
 
 	asset, err := smartContract.PatchAsset(mockedTransaction, "", emptyString)
 	assert.Equal(t, "", asset)
@@ -23,6 +24,7 @@ func Test_givenInvalidId_whenPatchAsset_thenException(t *testing.T) {
 func Test_givenValidIdButAssetDoesNotExist_whenPatchAsset_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
 	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode)
@@ -36,6 +38,7 @@ func Test_givenValidIdButAssetDoesNotExist_whenPatchAsset_thenException(t *testi
 func Test_givenNilStructure_whenPatchAsset_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
 	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode)
@@ -50,6 +53,7 @@ func Test_givenNilStructure_whenPatchAsset_thenException(t *testing.T) {
 func Test_givenNothingToPut_whenPatchAsset_thenException(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
 	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
 
 	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode)
@@ -68,18 +72,22 @@ func Test_givenNothingToPut_whenPatchAsset_thenException(t *testing.T) {
 func Test_givenSomethingToPut_whenPatchAsset_thenReturnAsset(t *testing.T) {
 	controller := gomock.NewController(t)
 	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
 	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincode, nil)
 
-	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode).Times(4)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode).Times(13)
+	mockedChaincode.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
 
 	assetToPut := &dtos.PutAssetRequest{
-		Hash: utils.RemoveStringSpaces("something"),
+		Hash: newHash,
 	}
 	encoded, err := json.Marshal(assetToPut)
 	assert.Nil(t, err)
 
 	givenAsset := &dtos.AssetRequest{
 		TypeForm: "something2",
+		Owner:    normalOwner,
 	}
 	encodedAssetFromDb, err := json.Marshal(givenAsset)
 	assert.Nil(t, err)
@@ -87,6 +95,11 @@ func Test_givenSomethingToPut_whenPatchAsset_thenReturnAsset(t *testing.T) {
 	mockedChaincode.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAssetFromDb, nil).Times(3)
 
 	mockedChaincode.EXPECT().PutState(utils.RemoveStringSpaces(normalId), gomock.Any()).Times(1)
+	mockHashIndex(mockedChaincode)
+	mockedChaincode.EXPECT().DelState(hashIndexKey("", utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincode.EXPECT().PutState(hashIndexKey(newHash, utils.RemoveStringSpaces(normalId)), []byte{0x00}).Return(nil)
+	mockedChaincode.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincode.EXPECT().SetEvent("FormPatched", gomock.Any()).Return(nil)
 
 	resultString, err := smartContract.PatchAsset(mockedTransaction, string(encoded), normalId)
 	assert.Nil(t, err)
@@ -98,4 +111,68 @@ func Test_givenSomethingToPut_whenPatchAsset_thenReturnAsset(t *testing.T) {
 	assert.NotNil(t, asset)
 	assert.Equal(t, asset.Hash, assetToPut.Hash)
 	assert.Equal(t, asset.TypeForm, givenAsset.TypeForm)
+	assert.Equal(t, asset.UpdatedAt.Equal(normalTxTime), true)
+}
+
+func Test_givenEditorWhoIsNotTheOwner_whenPatchAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincode, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode).Times(2)
+
+	assetToPut := &dtos.PutAssetRequest{
+		Hash: "something",
+	}
+	encoded, err := json.Marshal(assetToPut)
+	assert.Nil(t, err)
+
+	givenAsset := &dtos.AssetRequest{
+		TypeForm: "something2",
+		Owner: dtos.Identity{
+			MspId:   "Org2MSP",
+			Subject: "CN=user2",
+		},
+	}
+	encodedAssetFromDb, err := json.Marshal(givenAsset)
+	assert.Nil(t, err)
+
+	mockedChaincode.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAssetFromDb, nil).Times(2)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, string(encoded), normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "only the owner or an admin can change the asset")
+}
+
+func Test_givenDeletedAsset_whenPatchAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincode := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincode, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincode).Times(4)
+
+	assetToPut := &dtos.PutAssetRequest{
+		Hash: "something",
+	}
+	encoded, err := json.Marshal(assetToPut)
+	assert.Nil(t, err)
+
+	givenAsset := &dtos.AssetRequest{
+		TypeForm: "something2",
+		Deleted:  true,
+	}
+	encodedAssetFromDb, err := json.Marshal(givenAsset)
+	assert.Nil(t, err)
+
+	mockedChaincode.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAssetFromDb, nil).Times(2)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, string(encoded), normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset is deleted")
 }
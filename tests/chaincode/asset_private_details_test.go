@@ -0,0 +1,223 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var privateCollection = "formPrivateDetails"
+
+func Test_givenPrivateDetails_whenCreateAsset_thenStoreThemInTheCollection(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, PrivateCollection: privateCollection})
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	storedAsset := &dtos.AssetRequest{}
+	storedDetails := &dtos.AssetPrivateDetails{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"private_details": []byte(`{"national_id":"123"}`)}, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		return json.Unmarshal(value, storedAsset)
+	})
+	mockedChaincodeStub.EXPECT().PutPrivateData(privateCollection, "form_1", gomock.Any()).DoAndReturn(func(collection string, key string, value []byte) error {
+		return json.Unmarshal(value, storedDetails)
+	})
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil)
+
+	_, err = smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Nil(t, err)
+
+	assert.Equal(t, privateCollection, storedAsset.PrivateCollection)
+	assert.Nil(t, storedAsset.Fields)
+	assert.Equal(t, &dtos.AssetPrivateDetails{
+		DocType: "form_private_details",
+		Id:      "form_1",
+		Content: map[string]interface{}{"national_id": "123"},
+	}, storedDetails)
+}
+
+func Test_givenNoPrivateDetails_whenCreateAsset_thenOnlyStoreThePublicAsset(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, PrivateCollection: privateCollection})
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	storedAsset := &dtos.AssetRequest{}
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{}, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		return json.Unmarshal(value, storedAsset)
+	})
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormCreated", gomock.Any()).Return(nil)
+
+	_, err = smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Nil(t, err)
+	assert.Equal(t, "", storedAsset.PrivateCollection)
+}
+
+func Test_givenInvalidPrivateDetails_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true, PrivateCollection: privateCollection})
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"private_details": []byte(`"123"`)}, nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the private details are not a valid json object")
+}
+
+func Test_givenPrivateDetailsWithoutCollection_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"private_details": []byte(`{"national_id":"123"}`)}, nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the private details can't be stored since no private collection is configured")
+}
+
+func Test_givenPrivateAsset_whenDeleteAssetById_thenDeleteThePrivateDetails(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withPrivateCollection(privateCollection)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().DelState("form_1").Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, "form_1")).Return(nil)
+	mockedChaincodeStub.EXPECT().DelPrivateData(privateCollection, "form_1").Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, "form_1", "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+}
+
+func Test_givenPrivateAsset_whenDeleteAssets_thenDeleteThePrivateDetails(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withPrivateCollection(privateCollection)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().DelState("form_1").Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, "form_1")).Return(nil)
+	mockedChaincodeStub.EXPECT().DelPrivateData(privateCollection, "form_1").Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormsDeleted", gomock.Any()).Return(nil)
+
+	_, err := smartContract.DeleteAssets(mockedTransaction, `{"ids":["form_1"]}`, "")
+	assert.Nil(t, err)
+}
+
+func Test_givenPublicAsset_whenGetAssetPrivateDetails_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).Times(2)
+
+	result, err := smartContract.GetAssetPrivateDetails(mockedTransaction, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset has no private details")
+}
+
+func Test_givenCallerFromAnotherOrg_whenGetAssetPrivateDetails_thenException(t *testing.T) {
+	t.Setenv("CORE_PEER_LOCALMSPID", "Org2MSP")
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withPrivateCollection(privateCollection)), nil).Times(2)
+
+	result, err := smartContract.GetAssetPrivateDetails(mockedTransaction, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the caller msp Org1MSP is not the peer msp Org2MSP")
+}
+
+func Test_givenMissingDetailsOnThePeer_whenGetAssetPrivateDetails_thenException(t *testing.T) {
+	t.Setenv("CORE_PEER_LOCALMSPID", normalMspId)
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withPrivateCollection(privateCollection)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetPrivateData(privateCollection, "form_1").Return(nil, nil)
+
+	result, err := smartContract.GetAssetPrivateDetails(mockedTransaction, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the private details are not available on this peer")
+}
+
+func Test_givenCollectionMember_whenGetAssetPrivateDetails_thenReturnThem(t *testing.T) {
+	t.Setenv("CORE_PEER_LOCALMSPID", normalMspId)
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	details := `{"doc_type":"form_private_details","id":"form_1","content":{"national_id":"123"}}`
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withPrivateCollection(privateCollection)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetPrivateData(privateCollection, "form_1").Return([]byte(details), nil)
+
+	result, err := smartContract.GetAssetPrivateDetails(mockedTransaction, "form_1")
+	assert.Nil(t, err)
+	assert.Equal(t, details, result)
+}
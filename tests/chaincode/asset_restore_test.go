@@ -0,0 +1,131 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var deletedAssetRestore = &dtos.AssetRequest{
+	Id:      "form_1",
+	Hash:    "some_hash",
+	Deleted: true,
+	Deletion: &dtos.Deletion{
+		Reason:    "duplicated form",
+		DeletedBy: dtos.Identity{MspId: "Org1MSP", Subject: "CN=admin"},
+	},
+}
+
+func Test_givenEditor_whenRestoreAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.RestoreAsset(mockedTransaction, deletedAssetRestore.Id)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role editor is not allowed to perform this operation")
+}
+
+func Test_givenNonExistentAsset_whenRestoreAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(deletedAssetRestore.Id).Return(nil, nil)
+
+	result, err := smartContract.RestoreAsset(mockedTransaction, deletedAssetRestore.Id)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset doesn't exist")
+}
+
+func Test_givenAssetNotDeleted_whenRestoreAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	encodedAsset, err := json.Marshal(&dtos.AssetRequest{Id: deletedAssetRestore.Id})
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(deletedAssetRestore.Id).Return(encodedAsset, nil).Times(2)
+
+	result, err := smartContract.RestoreAsset(mockedTransaction, deletedAssetRestore.Id)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset is not deleted")
+}
+
+func Test_givenLedgerError_whenRestoreAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	encodedAsset, err := json.Marshal(deletedAssetRestore)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(deletedAssetRestore.Id).Return(encodedAsset, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(deletedAssetRestore.Id, gomock.Any()).Return(fmt.Errorf("some exception"))
+
+	result, err := smartContract.RestoreAsset(mockedTransaction, deletedAssetRestore.Id)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error updating ledger")
+}
+
+func Test_givenDeletedAsset_whenRestoreAsset_thenRemoveTombstone(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	encodedAsset, err := json.Marshal(deletedAssetRestore)
+	assert.Nil(t, err)
+
+	expectedAsset := &dtos.AssetRequest{
+		Id:        deletedAssetRestore.Id,
+		Hash:      deletedAssetRestore.Hash,
+		UpdatedAt: normalTxTime,
+		UpdatedBy: normalOwner,
+		Version:   1,
+		DocType:   "form",
+	}
+	expectedEncodedAsset, err := json.Marshal(expectedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(deletedAssetRestore.Id)).Return(encodedAsset, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(deletedAssetRestore.Id, expectedEncodedAsset).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormRestored", event)
+
+	resultString, err := smartContract.RestoreAsset(mockedTransaction, deletedAssetRestore.Id)
+	assert.Nil(t, err)
+
+	result := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.False(t, result.Deleted)
+	assert.Nil(t, result.Deletion)
+	assert.Equal(t, []string{"deleted", "deletion"}, event.ChangedFields)
+}
@@ -0,0 +1,158 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var newOwnerTransfer = dtos.Identity{
+	MspId:   "Org2 MSP",
+	Subject: " CN=user2,OU=client ",
+}
+
+func Test_givenInvalidId_whenTransferOwnership_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+
+	result, err := smartContract.TransferOwnership(mockedTransaction, emptyString, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the id is not valid")
+}
+
+func Test_givenNonExistentAsset_whenTransferOwnership_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(nil, nil)
+
+	result, err := smartContract.TransferOwnership(mockedTransaction, normalId, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset doesn't exist")
+}
+
+func Test_givenInvalidNewOwner_whenTransferOwnership_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return([]byte{1}, nil)
+
+	encodedOwner, err := json.Marshal(&dtos.Identity{MspId: normalMspId})
+	assert.Nil(t, err)
+
+	result, err := smartContract.TransferOwnership(mockedTransaction, normalId, string(encodedOwner))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the new owner is not valid")
+}
+
+func Test_givenCallerIsNotTheOwner_whenTransferOwnership_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	storedAsset := &dtos.AssetRequest{
+		Id:    utils.RemoveStringSpaces(normalId),
+		Owner: dtos.Identity{MspId: "Org3MSP", Subject: "CN=user3"},
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).Times(2)
+
+	encodedOwner, err := json.Marshal(newOwnerTransfer)
+	assert.Nil(t, err)
+
+	result, err := smartContract.TransferOwnership(mockedTransaction, normalId, string(encodedOwner))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "only the owner or an admin can change the asset")
+}
+
+func Test_givenOwner_whenTransferOwnership_thenReturnAssetWithNewOwner(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	storedAsset := &dtos.AssetRequest{
+		Id:    utils.RemoveStringSpaces(normalId),
+		Owner: normalOwner,
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	expectedOwner := dtos.Identity{MspId: "Org2MSP", Subject: "CN=user2,OU=client"}
+	expectedAsset := &dtos.AssetRequest{
+		Id:        utils.RemoveStringSpaces(normalId),
+		Owner:     expectedOwner,
+		UpdatedAt: normalTxTime,
+		UpdatedBy: normalOwner,
+		Version:   1,
+		DocType:   "form",
+	}
+	expectedEncodedAsset, err := json.Marshal(expectedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(7)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).Times(3)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalId), expectedEncodedAsset).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormOwnershipTransferred", gomock.Any()).Return(nil)
+
+	encodedOwner, err := json.Marshal(newOwnerTransfer)
+	assert.Nil(t, err)
+
+	resultString, err := smartContract.TransferOwnership(mockedTransaction, normalId, string(encodedOwner))
+	assert.Nil(t, err)
+
+	result := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOwner, result.Owner)
+}
+
+func Test_givenAdminAndLedgerError_whenTransferOwnership_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	storedAsset := &dtos.AssetRequest{
+		Id:    utils.RemoveStringSpaces(normalId),
+		Owner: dtos.Identity{MspId: "Org3MSP", Subject: "CN=user3"},
+	}
+	encodedAsset, err := json.Marshal(storedAsset)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(4)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodedAsset, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalId), gomock.Any()).Return(fmt.Errorf("some exception"))
+
+	encodedOwner, err := json.Marshal(newOwnerTransfer)
+	assert.Nil(t, err)
+
+	result, err := smartContract.TransferOwnership(mockedTransaction, normalId, string(encodedOwner))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error updating ledger")
+}
@@ -0,0 +1,142 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+var verifiedDocument = []byte("the scanned form")
+
+func verifiedDocumentHash() string {
+	documentHash := sha256.Sum256(verifiedDocument)
+	return hex.EncodeToString(documentHash[:])
+}
+
+func Test_givenDocumentAndDigest_whenVerifyDocumentHash_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"document": verifiedDocument}, nil)
+
+	result, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", verifiedDocumentHash())
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "either the document or the digest is required")
+}
+
+func Test_givenNothingToVerify_whenVerifyDocumentHash_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{}, nil)
+
+	result, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", " ")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "either the document or the digest is required")
+}
+
+func Test_givenDeletedAsset_whenVerifyDocumentHash_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	deletedAsset := encodeHistoryValue(t, &dtos.AssetRequest{Id: "form_1", Hash: normalHash, Deleted: true})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(deletedAsset, nil).Times(2)
+
+	result, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", normalHash)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the asset is deleted")
+}
+
+func Test_givenMatchingDocument_whenVerifyDocumentHash_thenReturnTheAnchoringTransaction(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	anchoredAt := time.Date(2025, 4, 2, 8, 0, 0, 0, time.UTC)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(map[string][]byte{"document": verifiedDocument}, nil)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey("form_1").Return(mockedHistoryIterator, nil)
+	mockedHistoryIterator.EXPECT().HasNext().Return(true).Times(3)
+	mockedHistoryIterator.EXPECT().Next().Return(&queryresult.KeyModification{
+		TxId:      "tx_3",
+		Timestamp: timestamppb.New(anchoredAt.Add(time.Hour)),
+		Value:     encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())),
+	}, nil)
+	mockedHistoryIterator.EXPECT().Next().Return(&queryresult.KeyModification{
+		TxId:      "tx_2",
+		Timestamp: timestamppb.New(anchoredAt),
+		Value:     encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())),
+	}, nil)
+	mockedHistoryIterator.EXPECT().Next().Return(&queryresult.KeyModification{
+		TxId:      "tx_1",
+		Timestamp: timestamppb.New(anchoredAt.Add(-time.Hour)),
+		Value:     encodeStoredAsset(t, "form_1"),
+	}, nil)
+	mockedHistoryIterator.EXPECT().Close().Return(nil)
+
+	resultString, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", "")
+	assert.Nil(t, err)
+
+	result := &dtos.DocumentVerification{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, &dtos.DocumentVerification{
+		Id:            "form_1",
+		Match:         true,
+		Algorithm:     "sha256",
+		StoredHash:    verifiedDocumentHash(),
+		CandidateHash: verifiedDocumentHash(),
+		AnchoredTxId:  "tx_2",
+		AnchoredAt:    anchoredAt,
+	}, result)
+}
+
+func Test_givenDifferentDigest_whenVerifyDocumentHash_thenNoMatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedHistoryIterator := mocks.NewMockHistoryQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash())), nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetTransient().Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetHistoryForKey("form_1").Return(mockedHistoryIterator, nil)
+	mockHistory(mockedHistoryIterator, []*queryresult.KeyModification{
+		{TxId: "tx_1", Timestamp: normalTxTimestamp, Value: encodeStoredAsset(t, "form_1", withHash(verifiedDocumentHash()))},
+	})
+
+	resultString, err := smartContract.VerifyDocumentHash(mockedTransaction, "form_1", normalHash)
+	assert.Nil(t, err)
+
+	result := &dtos.DocumentVerification{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.False(t, result.Match)
+	assert.Equal(t, "tx_1", result.AnchoredTxId)
+}
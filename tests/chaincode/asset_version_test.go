@@ -0,0 +1,150 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"errors"
+	"form-chaincode/chaincode"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func encodePatchWithVersion(t *testing.T, expectedVersion int) string {
+	encodedPatch, err := json.Marshal(&dtos.PutAssetRequest{
+		Hash:            newHash,
+		ExpectedVersion: &expectedVersion,
+	})
+	assert.Nil(t, err)
+	return string(encodedPatch)
+}
+
+func Test_givenExpectedVersionMatches_whenPatchAsset_thenIncreaseVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(3)), nil).Times(3)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(utils.RemoveStringSpaces(normalId), gomock.Any()).Return(nil)
+
+	event := &dtos.AssetEvent{}
+	captureEvent(mockedChaincodeStub, "FormPatched", event)
+
+	resultString, err := smartContract.PatchAsset(mockedTransaction, encodePatchWithVersion(t, 3), normalId)
+	assert.Nil(t, err)
+
+	result := &dtos.AssetRequest{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, result.Version)
+	assert.Equal(t, []string{"hash"}, event.ChangedFields)
+}
+
+func Test_givenStaleExpectedVersion_whenPatchAsset_thenVersionConflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(5)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(4)), nil).Times(3)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, encodePatchWithVersion(t, 3), normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, chaincode.ErrVersionConflict))
+	assert.Equal(t, err.Error(), "version conflict: the asset is at version 4 but version 3 was expected")
+}
+
+func Test_givenInvalidExpectedVersion_whenDeleteAssetById_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(1)), nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "first")
+	assert.Equal(t, false, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the expected version first is not valid")
+}
+
+func Test_givenStaleExpectedVersion_whenDeleteAssetById_thenVersionConflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(2)), nil).Times(2)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "1")
+	assert.Equal(t, false, result)
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, chaincode.ErrVersionConflict))
+}
+
+func Test_givenExpectedVersionMatches_whenDeleteAssetById_thenDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(2)), nil).Times(3)
+	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", " 2 ")
+	assert.Equal(t, true, result)
+	assert.Nil(t, err)
+}
+
+func Test_givenNegativeExpectedVersion_whenPatchAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(1)), nil)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, encodePatchWithVersion(t, -1), normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the expected version -1 is not valid")
+}
+
+func Test_givenZeroExpectedVersionForLegacyAsset_whenDeleteAssetById_thenDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId), withVersion(0)), nil).Times(3)
+	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "0")
+	assert.Equal(t, true, result)
+	assert.Nil(t, err)
+}
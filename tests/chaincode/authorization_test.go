@@ -0,0 +1,144 @@
+package chaincode
+
+import (
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"form-chaincode/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_givenErrorGettingMspId_whenCreateAssetAsAuditor_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity).Times(1)
+	mockedIdentity.EXPECT().GetMSPID().Return("", fmt.Errorf("some exception"))
+
+	result, err := smartContract.CreateAsset(mockedTransaction, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error getting the caller msp id")
+}
+
+func Test_givenCallerWithoutRole_whenGetAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity).Times(2)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedIdentity.EXPECT().GetMSPID().Return(normalMspId, nil)
+	mockedIdentity.EXPECT().GetAttributeValue("form.role").Return("", false, nil)
+
+	result, err := smartContract.GetAssetById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the caller has no role assigned")
+}
+
+func Test_givenCallerWithUnknownRole_whenGetAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "superuser")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.GetAssetById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role superuser is not valid")
+}
+
+func Test_givenRoleNotGrantedToTheCallerMsp_whenGetAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{
+		AdminMspIds: []string{"AdminMSP"},
+		MspRoles:    map[string][]string{"Org2MSP": {"admin"}, normalMspId: {"submitter"}},
+	})
+
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity).Times(2)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(2)
+	mockedIdentity.EXPECT().GetMSPID().Return(normalMspId, nil)
+	mockedIdentity.EXPECT().GetAttributeValue("form.role").Return("admin", true, nil)
+
+	result, err := smartContract.GetAssetById(mockedTransaction, normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role admin is not granted to the msp "+normalMspId)
+}
+
+func Test_givenAuditor_whenCreateAsset_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.CreateAsset(mockedTransaction, "")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role auditor is not allowed to perform this operation")
+}
+
+func Test_givenSubmitter_whenPatchAsset_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.PatchAsset(mockedTransaction, "", normalId)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role submitter is not allowed to perform this operation")
+}
+
+func Test_givenEditor_whenDeleteAssetById_thenReturnException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
+	assert.Equal(t, false, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role editor is not allowed to perform this operation")
+}
+
+func Test_givenCallerFromAdminMsp_whenDeleteAssetById_thenSuccess(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AdminMspIds: []string{"OtherMSP", normalMspId}})
+
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity).Times(2)
+	mockedIdentity.EXPECT().GetMSPID().Return(normalMspId, nil).Times(2)
+	mockedIdentity.EXPECT().GetX509Certificate().Return(normalCertificate, nil)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).Times(11)
+	mockedChaincodeStub.EXPECT().GetState(utils.RemoveStringSpaces(normalId)).Return(encodeStoredAsset(t, utils.RemoveStringSpaces(normalId)), nil).Times(2)
+	mockedChaincodeStub.EXPECT().DelState(utils.RemoveStringSpaces(normalId)).Return(nil)
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKey(normalHash, utils.RemoveStringSpaces(normalId))).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormDeleted", gomock.Any()).Return(nil)
+
+	result, err := smartContract.DeleteAssetById(mockedTransaction, normalId, "", "")
+	assert.Equal(t, true, result)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,189 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_givenEditor_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"admin_msp_ids":["Org1MSP"]}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role editor is not allowed to perform this operation")
+}
+
+func Test_givenEmptyAdminMspId_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"admin_msp_ids":["Org1MSP"," "]}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the admin msp ids are not valid")
+}
+
+func Test_givenInvalidDeleteMode_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"delete_mode":"archive"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the delete mode archive is not valid")
+}
+
+func Test_givenInvalidPolicy_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"duplicate_hash_policy":"sometimes"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the duplicate hash policy sometimes is not valid")
+}
+
+func Test_givenInvalidMspRole_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"admin_msp_ids":["Org1MSP"],"msp_roles":{"Org2MSP":["superuser"]}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role superuser is not valid")
+}
+
+func Test_givenNoAdminMspId_whenSetChaincodeConfig_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"delete_mode":"soft"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the config needs at least one admin msp id")
+}
+
+func Test_givenStoredConfig_whenSetChaincodeConfig_thenIncreaseVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DocType: "config", Version: 2})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(configKey, gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.SetChaincodeConfig(mockedTransaction, `{"admin_msp_ids":[" Org1MSP"],"msp_roles":{"Org2MSP":[" Editor"]},"delete_mode":"SOFT","duplicate_hash_policy":"warn"}`)
+	assert.Nil(t, err)
+
+	result := &dtos.ChaincodeConfig{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, "config", result.DocType)
+	assert.Equal(t, []string{"Org1MSP"}, result.AdminMspIds)
+	assert.Equal(t, map[string][]string{"Org2MSP": {"editor"}}, result.MspRoles)
+	assert.Equal(t, "soft", result.DeleteMode)
+	assert.Equal(t, "warn", result.DuplicateHashPolicy)
+	assert.Equal(t, 100, result.MaxBatchSize)
+	assert.Equal(t, 3, result.Version)
+	assert.Equal(t, normalTxTime, result.UpdatedAt)
+	assert.Equal(t, normalOwner, result.UpdatedBy)
+}
+
+func Test_givenNoConfig_whenGetChaincodeConfig_thenReturnDefaults(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockNoConfig(mockedChaincodeStub)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	resultString, err := smartContract.GetChaincodeConfig(mockedTransaction)
+	assert.Nil(t, err)
+
+	result := &dtos.ChaincodeConfig{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, result.AdminMspIds)
+	assert.Equal(t, "hard", result.DeleteMode)
+	assert.Equal(t, 100, result.MaxBatchSize)
+	assert.Equal(t, false, result.AllowUnregisteredFormTypes)
+	assert.Equal(t, false, result.AllowUnregisteredInsertionTypes)
+	assert.Equal(t, 16384, result.MaxFieldsSize)
+	assert.Equal(t, "allow", result.DuplicateHashPolicy)
+	assert.Equal(t, 0, result.Version)
+}
+
+func Test_givenNoConfig_whenInitLedger_thenSeedTheAdminMsps(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockNoConfig(mockedChaincodeStub)
+
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity)
+	mockedIdentity.EXPECT().GetMSPID().Return(normalMspId, nil)
+	mockedIdentity.EXPECT().GetX509Certificate().Return(normalCertificate, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+
+	var storedConfig []byte
+	mockedChaincodeStub.EXPECT().PutState(configKey, gomock.Any()).DoAndReturn(func(key string, value []byte) error {
+		storedConfig = value
+		return nil
+	})
+
+	err := smartContract.InitLedger(mockedTransaction, `{"admin_msp_ids":[" Org1MSP"]}`)
+	assert.Nil(t, err)
+
+	result := &dtos.ChaincodeConfig{}
+	err = json.Unmarshal(storedConfig, result)
+	assert.Nil(t, err)
+	assert.Equal(t, "config", result.DocType)
+	assert.Equal(t, []string{"Org1MSP"}, result.AdminMspIds)
+	assert.Equal(t, 1, result.Version)
+	assert.Equal(t, normalOwner, result.UpdatedBy)
+}
+
+func Test_givenStoredConfig_whenInitLedger_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{DocType: "config", AdminMspIds: []string{"Org1MSP"}, Version: 1})
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	err := smartContract.InitLedger(mockedTransaction, `{"admin_msp_ids":["Org2MSP"]}`)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the config is already set")
+}
+
+func Test_givenNoAdminMspId_whenInitLedger_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockNoConfig(mockedChaincodeStub)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	err := smartContract.InitLedger(mockedTransaction, `{}`)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the config needs at least one admin msp id")
+}
@@ -0,0 +1,232 @@
+package chaincode
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var indexesPath = "../../deploy/META-INF/statedb/couchdb/indexes"
+var codePackagePath = "../../deploy/code.tar.gz"
+
+type couchdbIndex struct {
+	Index struct {
+		Fields []string `json:"fields"`
+	} `json:"index"`
+	Ddoc string `json:"ddoc"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func readIndexes(t *testing.T) map[string][]byte {
+	files, err := filepath.Glob(filepath.Join(indexesPath, "*.json"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, files)
+
+	indexes := map[string][]byte{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.Nil(t, err)
+		indexes[filepath.Base(file)] = content
+	}
+	return indexes
+}
+
+func captureQuery(t *testing.T, filter *dtos.Filter) map[string]interface{} {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	encodedFilter, err := json.Marshal(filter)
+	assert.Nil(t, err)
+
+	query := ""
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetQueryResultWithPagination(gomock.Any(), int32(1), "").DoAndReturn(
+		func(value string, size int32, bookmark string) (interface{}, interface{}, error) {
+			query = value
+			return nil, nil, fmt.Errorf("stop")
+		},
+	)
+
+	_, err = smartContract.GetAllAssets(mockedTransaction, "0", "1", string(encodedFilter))
+	assert.NotNil(t, err)
+
+	decodedQuery := map[string]interface{}{}
+	err = json.Unmarshal([]byte(query), &decodedQuery)
+	assert.Nil(t, err)
+	return decodedQuery
+}
+
+// getSelectorFields returns the fields of the selector, the fields of the $or and $and clauses included
+func getSelectorFields(selector map[string]interface{}) []string {
+	fields := []string{}
+	for field, condition := range selector {
+		if field != "$or" && field != "$and" {
+			fields = append(fields, field)
+			continue
+		}
+		for _, clause := range condition.([]interface{}) {
+			fields = append(fields, getSelectorFields(clause.(map[string]interface{}))...)
+		}
+	}
+	return fields
+}
+
+// matchesSelector evaluates the operators used by the queries the way couchdb does, a missing field only
+// matches $exists false
+func matchesSelector(t *testing.T, selector map[string]interface{}, document map[string]interface{}) bool {
+	for field, condition := range selector {
+		switch field {
+		case "$or":
+			matched := false
+			for _, clause := range condition.([]interface{}) {
+				matched = matched || matchesSelector(t, clause.(map[string]interface{}), document)
+			}
+			if !matched {
+				return false
+			}
+		case "$and":
+			for _, clause := range condition.([]interface{}) {
+				if !matchesSelector(t, clause.(map[string]interface{}), document) {
+					return false
+				}
+			}
+		default:
+			if !matchesCondition(t, condition, document, field) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchesCondition(t *testing.T, condition interface{}, document map[string]interface{}, field string) bool {
+	value, exists := document[field]
+	operators, isOperator := condition.(map[string]interface{})
+	if !isOperator {
+		return exists && value == condition
+	}
+
+	for operator, operand := range operators {
+		switch operator {
+		case "$exists":
+			if exists != operand.(bool) {
+				return false
+			}
+		case "$in":
+			found := false
+			for _, item := range operand.([]interface{}) {
+				found = found || (exists && value == item)
+			}
+			if !found {
+				return false
+			}
+		default:
+			t.Fatalf("the operator %s is not supported", operator)
+		}
+	}
+	return true
+}
+
+func Test_givenIndexFiles_thenAllAreValidJsonIndexes(t *testing.T) {
+	for name, content := range readIndexes(t) {
+		index := &couchdbIndex{}
+		err := json.Unmarshal(content, index)
+		assert.Nil(t, err, name)
+		assert.NotEmpty(t, index.Index.Fields, name)
+		assert.NotEmpty(t, index.Ddoc, name)
+		assert.NotEmpty(t, index.Name, name)
+		assert.Equal(t, "json", index.Type, name)
+	}
+}
+
+func Test_givenEveryFilterField_whenCreateQuery_thenEveryFieldHasAnIndex(t *testing.T) {
+	indexedFields := map[string]bool{}
+	for _, content := range readIndexes(t) {
+		index := &couchdbIndex{}
+		err := json.Unmarshal(content, index)
+		assert.Nil(t, err)
+		indexedFields[index.Index.Fields[0]] = true
+	}
+
+	for _, timeField := range []string{"declared", "recorded", "updated"} {
+		filter := &dtos.Filter{
+			Ids:            []string{normalId},
+			TypeForms:      []string{normalTypeForm},
+			InsertionTypes: []string{normalInsertionType},
+			Hashs:          []string{normalHash},
+			TimeFilter: dtos.TimestampFilter{
+				Field: timeField,
+				Min:   normalTimestamp,
+				Max:   normalTimestamp.Add(time.Minute),
+			},
+		}
+
+		selector := captureQuery(t, filter)["selector"].(map[string]interface{})
+		for _, field := range getSelectorFields(selector) {
+			assert.True(t, indexedFields[field], "the field %s has no index", field)
+		}
+	}
+}
+
+func Test_givenEverySortField_whenCreateQuery_thenEverySortHasAnIndex(t *testing.T) {
+	indexedFields := map[string]bool{}
+	for _, content := range readIndexes(t) {
+		index := &couchdbIndex{}
+		err := json.Unmarshal(content, index)
+		assert.Nil(t, err)
+		if len(index.Index.Fields) == 1 {
+			indexedFields[index.Index.Fields[0]] = true
+		}
+	}
+
+	for _, field := range []string{"timestamp", "type_form", "insertion_type", "id"} {
+		filter := &dtos.Filter{
+			Sort: []dtos.SortField{{Field: field}},
+		}
+
+		sort := captureQuery(t, filter)["sort"].([]interface{})
+		for sortField := range sort[0].(map[string]interface{}) {
+			assert.True(t, indexedFields[sortField], "the sort field %s has no index", sortField)
+		}
+	}
+}
+
+func Test_givenCodePackage_thenItHasEveryIndex(t *testing.T) {
+	file, err := os.Open(codePackagePath)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	assert.Nil(t, err)
+
+	packagedIndexes := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+
+		if header.Typeflag == tar.TypeReg && filepath.Dir(header.Name) == "META-INF/statedb/couchdb/indexes" {
+			content, err := io.ReadAll(tarReader)
+			assert.Nil(t, err)
+			packagedIndexes[filepath.Base(header.Name)] = content
+		}
+	}
+
+	assert.Equal(t, readIndexes(t), packagedIndexes)
+}
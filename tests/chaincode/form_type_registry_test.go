@@ -0,0 +1,283 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+var formTypeKey = "form_type_key"
+var formTypeSchema = `{"type":"object","required":["country"],"properties":{"country":{"type":"string","minLength":2}}}`
+
+func encodeFormType(t *testing.T, name string, schema string) []byte {
+	encodedFormType, err := json.Marshal(&dtos.FormType{
+		DocType: "form_type",
+		Name:    name,
+		Schema:  json.RawMessage(schema),
+		Version: 1,
+	})
+	assert.Nil(t, err)
+	return encodedFormType
+}
+
+func Test_givenEditor_whenRegisterFormType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.RegisterFormType(mockedTransaction, `{"name":"tax","schema":{}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role editor is not allowed to perform this operation")
+}
+
+func Test_givenInvalidSchema_whenRegisterFormType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.RegisterFormType(mockedTransaction, `{"name":"tax","schema":{"type":"unknown"}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the schema is not valid")
+}
+
+func Test_givenMissingName_whenRegisterFormType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.RegisterFormType(mockedTransaction, `{"name":" ","schema":{}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the name is not valid")
+}
+
+func Test_givenRegisteredName_whenRegisterFormType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{"tax"}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(encodeFormType(t, "tax", formTypeSchema), nil)
+
+	result, err := smartContract.RegisterFormType(mockedTransaction, `{"name":"tax","schema":{}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the form type tax is already registered")
+}
+
+func Test_givenNewName_whenRegisterFormType_thenSaveFirstVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{"tax"}).Return(formTypeKey, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(formTypeKey, gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.RegisterFormType(mockedTransaction, `{"name":" tax","description":"tax forms","schema":`+formTypeSchema+`}`)
+	assert.Nil(t, err)
+
+	result := &dtos.FormType{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, "form_type", result.DocType)
+	assert.Equal(t, "tax", result.Name)
+	assert.Equal(t, "tax forms", result.Description)
+	assert.JSONEq(t, formTypeSchema, string(result.Schema))
+	assert.Equal(t, 1, result.Version)
+	assert.Equal(t, normalTxTime, result.UpdatedAt)
+	assert.Equal(t, normalOwner, result.UpdatedBy)
+}
+
+func Test_givenUnknownName_whenUpdateFormType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{"tax"}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(nil, nil)
+
+	result, err := smartContract.UpdateFormType(mockedTransaction, `{"name":"tax","schema":{}}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the form type tax is not registered")
+}
+
+func Test_givenRegisteredName_whenUpdateFormType_thenIncreaseVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{"tax"}).Return(formTypeKey, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(encodeFormType(t, "tax", formTypeSchema), nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(formTypeKey, gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.UpdateFormType(mockedTransaction, `{"name":"tax","schema":{"type":"object"}}`)
+	assert.Nil(t, err)
+
+	result := &dtos.FormType{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Version)
+	assert.JSONEq(t, `{"type":"object"}`, string(result.Schema))
+}
+
+func Test_givenRegisteredTypes_whenListFormTypes_thenReturnAll(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetStateByPartialCompositeKey("form_type", []string{}).Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(2)
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: encodeFormType(t, "tax", formTypeSchema)}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: encodeFormType(t, "visa", "{}")}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	resultString, err := smartContract.ListFormTypes(mockedTransaction)
+	assert.Nil(t, err)
+
+	result := []*dtos.FormType{}
+	err = json.Unmarshal([]byte(resultString), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "tax", result[0].Name)
+	assert.Equal(t, "visa", result[1].Name)
+}
+
+func Test_givenNoConfigAndUnregisteredTypeForm_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockNoConfig(mockedChaincodeStub)
+
+	encodedRequest, err := json.Marshal(newBulkRequest("form_1"))
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{normalTypeForm}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(nil, nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the form type "+normalTypeForm+" is not registered")
+}
+
+func Test_givenAssetNotMatchingTheSchema_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{})
+
+	request := newBulkRequest("form_1")
+	request.Fields = map[string]interface{}{"country": "F"}
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{normalTypeForm}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(encodeFormType(t, normalTypeForm, formTypeSchema), nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "the asset doesn't match the schema of the form type "+normalTypeForm)
+	assert.Contains(t, err.Error(), "country")
+}
+
+func Test_givenUnregisteredTypeForm_whenCreateAssets_thenRejectTheItem(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredInsertionTypes: true})
+
+	unregistered := newBulkRequest("form_2")
+	unregistered.TypeForm = "visa"
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{normalTypeForm}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(encodeFormType(t, normalTypeForm, formTypeSchema), nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{"visa"}).Return("visa_key", nil)
+	mockedChaincodeStub.EXPECT().GetState("visa_key").Return(nil, nil)
+
+	valid := newBulkRequest("form_1")
+	valid.Fields = map[string]interface{}{"country": "FR"}
+
+	result, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t, valid, unregistered))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+
+	itemErrors := []*dtos.BatchItemError{}
+	err = json.Unmarshal([]byte(strings.TrimPrefix(err.Error(), "the batch was rejected ")), &itemErrors)
+	assert.Nil(t, err)
+	assert.Equal(t, []*dtos.BatchItemError{{Index: 1, Id: "form_2", Error: "the form type visa is not registered"}}, itemErrors)
+}
+
+func Test_givenAssetMatchingTheSchema_whenPatchAsset_thenPatchIt(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("form_type", []string{normalTypeForm}).Return(formTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(formTypeKey).Return(encodeFormType(t, normalTypeForm, formTypeSchema), nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormPatched", gomock.Any()).Return(nil)
+
+	_, err := smartContract.PatchAsset(mockedTransaction, `{"fields":{"country":"FR"}}`, "form_1")
+	assert.Nil(t, err)
+}
+
+func Test_givenAssetStoredBeforeTheSchema_whenPatchAssetDescription_thenPatchItWithoutValidating(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormPatched", gomock.Any()).Return(nil)
+
+	_, err := smartContract.PatchAsset(mockedTransaction, `{"description":"a new description"}`, "form_1")
+	assert.Nil(t, err)
+}
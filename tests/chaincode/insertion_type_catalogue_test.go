@@ -0,0 +1,244 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+var insertionTypeKey = "insertion_type_key"
+
+func encodeInsertionType(t *testing.T, code string, active bool) []byte {
+	encodedInsertionType, err := json.Marshal(&dtos.InsertionType{
+		DocType: "insertion_type",
+		Code:    code,
+		Label:   "Manual",
+		Active:  active,
+		Version: 1,
+	})
+	assert.Nil(t, err)
+	return encodedInsertionType
+}
+
+func Test_givenSubmitter_whenRegisterInsertionType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, nil)
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+
+	result, err := smartContract.RegisterInsertionType(mockedTransaction, `{"code":"manual","label":"Manual"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the role submitter is not allowed to perform this operation")
+}
+
+func Test_givenMissingLabel_whenRegisterInsertionType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.RegisterInsertionType(mockedTransaction, `{"code":"manual"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the label is not valid")
+}
+
+func Test_givenNewCode_whenRegisterInsertionType_thenSaveItActive(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"manual"}).Return(insertionTypeKey, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(insertionTypeKey, gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.RegisterInsertionType(mockedTransaction, `{"code":" manual","label":" Manual "}`)
+	assert.Nil(t, err)
+
+	result := &dtos.InsertionType{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.Equal(t, &dtos.InsertionType{
+		DocType:   "insertion_type",
+		Code:      "manual",
+		Label:     "Manual",
+		Active:    true,
+		Version:   1,
+		UpdatedAt: normalTxTime,
+		UpdatedBy: normalOwner,
+	}, result)
+}
+
+func Test_givenRegisteredCode_whenRegisterInsertionType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"manual"}).Return(insertionTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(encodeInsertionType(t, "manual", true), nil)
+
+	result, err := smartContract.RegisterInsertionType(mockedTransaction, `{"code":"manual","label":"Manual"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the insertion type manual is already registered")
+}
+
+func Test_givenNothingToChange_whenUpdateInsertionType_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+
+	result, err := smartContract.UpdateInsertionType(mockedTransaction, `{"code":"manual"}`)
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "nothing to change in the request")
+}
+
+func Test_givenInactiveFlag_whenUpdateInsertionType_thenDeprecateIt(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "admin")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"manual"}).Return(insertionTypeKey, nil).Times(2)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(encodeInsertionType(t, "manual", true), nil)
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState(insertionTypeKey, gomock.Any()).Return(nil)
+
+	resultString, err := smartContract.UpdateInsertionType(mockedTransaction, `{"code":"manual","active":false}`)
+	assert.Nil(t, err)
+
+	result := &dtos.InsertionType{}
+	err = json.Unmarshal([]byte(resultString), result)
+	assert.Nil(t, err)
+	assert.False(t, result.Active)
+	assert.Equal(t, "Manual", result.Label)
+	assert.Equal(t, 2, result.Version)
+}
+
+func Test_givenCatalogue_whenListInsertionTypes_thenReturnActiveAndInactive(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "auditor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockedIterator := mocks.NewMockStateQueryIteratorInterface(controller)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetStateByPartialCompositeKey("insertion_type", []string{}).Return(mockedIterator, nil)
+	mockedIterator.EXPECT().HasNext().Return(true).Times(2)
+	mockedIterator.EXPECT().HasNext().Return(false)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: encodeInsertionType(t, "manual", true)}, nil)
+	mockedIterator.EXPECT().Next().Return(&queryresult.KV{Value: encodeInsertionType(t, "scan", false)}, nil)
+	mockedIterator.EXPECT().Close().Return(nil)
+
+	resultString, err := smartContract.ListInsertionTypes(mockedTransaction)
+	assert.Nil(t, err)
+
+	result := []*dtos.InsertionType{}
+	err = json.Unmarshal([]byte(resultString), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.True(t, result[0].Active)
+	assert.False(t, result[1].Active)
+}
+
+func Test_givenUnknownInsertionType_whenCreateAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true})
+
+	request := newBulkRequest("form_1")
+	request.InsertionType = "manaul"
+	encodedRequest, err := json.Marshal(request)
+	assert.Nil(t, err)
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"manaul"}).Return(insertionTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(nil, nil)
+
+	result, err := smartContract.CreateAsset(mockedTransaction, string(encodedRequest))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the insertion type manaul is not registered")
+}
+
+func Test_givenInactiveInsertionType_whenCreateAssets_thenRejectTheItem(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "submitter")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true})
+
+	deprecated := newBulkRequest("form_2")
+	deprecated.InsertionType = "scan"
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().GetState("form_2").Return(nil, nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{normalInsertionType}).Return("manual_key", nil)
+	mockedChaincodeStub.EXPECT().GetState("manual_key").Return(encodeInsertionType(t, normalInsertionType, true), nil)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"scan"}).Return(insertionTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(encodeInsertionType(t, "scan", false), nil)
+
+	result, err := smartContract.CreateAssets(mockedTransaction, encodeBulkRequests(t, newBulkRequest("form_1"), deprecated))
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+
+	itemErrors := []*dtos.BatchItemError{}
+	err = json.Unmarshal([]byte(strings.TrimPrefix(err.Error(), "the batch was rejected ")), &itemErrors)
+	assert.Nil(t, err)
+	assert.Equal(t, []*dtos.BatchItemError{{Index: 1, Id: "form_2", Error: "the insertion type scan is not active"}}, itemErrors)
+}
+
+func Test_givenInactiveInsertionType_whenPatchAsset_thenException(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("insertion_type", []string{"scan"}).Return(insertionTypeKey, nil)
+	mockedChaincodeStub.EXPECT().GetState(insertionTypeKey).Return(encodeInsertionType(t, "scan", false), nil)
+
+	result, err := smartContract.PatchAsset(mockedTransaction, `{"insertion_type":"scan"}`, "form_1")
+	assert.Equal(t, "", result)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "the insertion type scan is not active")
+}
+
+func Test_givenAssetWithDeprecatedInsertionType_whenPatchAnotherField_thenPatchIt(t *testing.T) {
+	controller := gomock.NewController(t)
+	mockedTransaction := mocks.NewMockTransactionContextInterface(controller)
+	mockCallerWithRole(controller, mockedTransaction, "editor")
+	mockedChaincodeStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedChaincodeStub, &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true})
+
+	mockedTransaction.EXPECT().GetStub().Return(mockedChaincodeStub).AnyTimes()
+	mockHashIndexWrites(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().GetState("form_1").Return(encodeStoredAsset(t, "form_1"), nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetTxTimestamp().Return(normalTxTimestamp, nil)
+	mockedChaincodeStub.EXPECT().PutState("form_1", gomock.Any()).Return(nil)
+	mockedChaincodeStub.EXPECT().GetTxID().Return(normalTxId)
+	mockedChaincodeStub.EXPECT().SetEvent("FormPatched", gomock.Any()).Return(nil)
+
+	_, err := smartContract.PatchAsset(mockedTransaction, `{"hash":"`+newHash+`"}`, "form_1")
+	assert.Nil(t, err)
+}
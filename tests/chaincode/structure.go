@@ -1,5 +1,125 @@
 package chaincode
 
-import "form-chaincode/chaincode"
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"form-chaincode/chaincode"
+	"form-chaincode/dtos"
+	"form-chaincode/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
+	"testing"
+	"time"
+)
 
 var smartContract = chaincode.SmartContract{}
+
+var normalMspId = "Org1MSP"
+var normalTxId = "some_tx_id"
+var normalTxTime = time.Date(2025, 4, 5, 12, 30, 45, 0, time.UTC)
+var normalTxTimestamp = timestamppb.New(normalTxTime)
+var normalCertificate = &x509.Certificate{Subject: pkix.Name{CommonName: "user1", OrganizationalUnit: []string{"client"}}}
+var normalTypeForm = "some_type_form"
+var normalHash = "d0eb35b028d6c3b63e064bd26de884a206a904d0367a0c47cd5d9413adf63069"
+var newHash = "0375426aeac6b8390281f504cf11cfb748b9f90c5c4600fbf0c2a97fbc1401e0"
+var normalOwner = dtos.Identity{MspId: normalMspId, Subject: normalCertificate.Subject.String()}
+
+func mockCallerWithRole(controller *gomock.Controller, mockedTransaction *mocks.MockTransactionContextInterface, role string) *mocks.MockClientIdentity {
+	mockedIdentity := mocks.NewMockClientIdentity(controller)
+	mockedTransaction.EXPECT().GetClientIdentity().Return(mockedIdentity).AnyTimes()
+	mockedIdentity.EXPECT().GetMSPID().Return(normalMspId, nil).AnyTimes()
+	mockedIdentity.EXPECT().GetAttributeValue("form.role").Return(role, true, nil).AnyTimes()
+	mockedIdentity.EXPECT().GetX509Certificate().Return(normalCertificate, nil).AnyTimes()
+
+	// authorize reads the config with its first two GetStub calls, the later calls reach the stub of the test
+	mockedConfigStub := mocks.NewMockChaincodeStubInterface(controller)
+	mockConfig(mockedConfigStub, &dtos.ChaincodeConfig{MspRoles: map[string][]string{normalMspId: {role}}})
+	mockedTransaction.EXPECT().GetStub().Return(mockedConfigStub).MaxTimes(2)
+	return mockedIdentity
+}
+
+// encodeStoredAsset encodes an asset as the chaincode stores it, the updates change the fields the test needs
+func encodeStoredAsset(t *testing.T, id string, updates ...func(asset *dtos.AssetRequest)) []byte {
+	asset := &dtos.AssetRequest{
+		Id:            id,
+		TypeForm:      normalTypeForm,
+		Hash:          normalHash,
+		HashAlgorithm: "sha256",
+		Owner:         normalOwner,
+		Version:       1,
+		DocType:       "form",
+	}
+	for _, update := range updates {
+		update(asset)
+	}
+
+	encodedAsset, err := json.Marshal(asset)
+	assert.Nil(t, err)
+	return encodedAsset
+}
+
+func withVersion(version int) func(asset *dtos.AssetRequest) {
+	return func(asset *dtos.AssetRequest) { asset.Version = version }
+}
+
+func withDeleted(deleted bool) func(asset *dtos.AssetRequest) {
+	return func(asset *dtos.AssetRequest) { asset.Deleted = deleted }
+}
+
+func withHash(hash string) func(asset *dtos.AssetRequest) {
+	return func(asset *dtos.AssetRequest) { asset.Hash = hash }
+}
+
+func withPrivateCollection(collection string) func(asset *dtos.AssetRequest) {
+	return func(asset *dtos.AssetRequest) { asset.PrivateCollection = collection }
+}
+
+func hashIndexKey(hash string, id string) string {
+	return "\x00hash~id\x00" + hash + "\x00" + id + "\x00"
+}
+
+func mockHashIndex(mockedChaincodeStub *mocks.MockChaincodeStubInterface) {
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("hash~id", gomock.Any()).DoAndReturn(func(objectType string, attributes []string) (string, error) {
+		return hashIndexKey(attributes[0], attributes[1]), nil
+	}).AnyTimes()
+}
+
+type hashIndexKeyMatcher struct{}
+
+func (hashIndexKeyMatcher) Matches(key interface{}) bool {
+	return strings.HasPrefix(fmt.Sprint(key), "\x00hash~id\x00")
+}
+
+func (hashIndexKeyMatcher) String() string {
+	return "is a hash index key"
+}
+
+// mockHashIndexWrites accepts the hash index updates of the tests that don't check them
+func mockHashIndexWrites(mockedChaincodeStub *mocks.MockChaincodeStubInterface) {
+	mockHashIndex(mockedChaincodeStub)
+	mockedChaincodeStub.EXPECT().PutState(hashIndexKeyMatcher{}, []byte{0x00}).Return(nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().DelState(hashIndexKeyMatcher{}).Return(nil).AnyTimes()
+}
+
+var configKey = "\x00config\x00"
+
+// mockConfig stores the given chaincode config, nil stores a config opting out of the form type and insertion
+// type checks so the tests can use any type_form and insertion_type
+func mockConfig(mockedChaincodeStub *mocks.MockChaincodeStubInterface, config *dtos.ChaincodeConfig) {
+	if config == nil {
+		config = &dtos.ChaincodeConfig{AllowUnregisteredFormTypes: true, AllowUnregisteredInsertionTypes: true}
+	}
+	encodedConfig, _ := json.Marshal(config)
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("config", []string{}).Return(configKey, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(configKey).Return(encodedConfig, nil).AnyTimes()
+}
+
+// mockNoConfig leaves the chaincode config unset so the defaults apply
+func mockNoConfig(mockedChaincodeStub *mocks.MockChaincodeStubInterface) {
+	mockedChaincodeStub.EXPECT().CreateCompositeKey("config", []string{}).Return(configKey, nil).AnyTimes()
+	mockedChaincodeStub.EXPECT().GetState(configKey).Return(nil, nil).AnyTimes()
+}
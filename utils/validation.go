@@ -37,6 +37,37 @@ func ValidatePageAndSize(pageString string, sizeString string) (int, int, error)
 	return page, size, nil
 }
 
+func ValidateSize(sizeString string) (int, error) {
+	size, err := convertStringToInt(sizeString)
+	if err != nil {
+		return 0, err
+	}
+
+	if isNumberNegative(size) || !isNumberDifferentThatZero(size) {
+		return 0, fmt.Errorf("size is not valid")
+	}
+
+	return size, nil
+}
+
+func ValidateOffsetAndLimit(offsetString string, limitString string) (int, int, error) {
+	offset, err := convertStringToInt(offsetString)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit, err := convertStringToInt(limitString)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if isNumberNegative(offset) || isNumberNegative(limit) || !isNumberDifferentThatZero(limit) {
+		return 0, 0, fmt.Errorf("offset and limit are not consistent")
+	}
+
+	return offset, limit, nil
+}
+
 func arePageAndSizeLegit(page int, size int) bool {
 	return !isNumberNegative(page) && !isNumberNegative(size) && isNumberDifferentThatZero(size)
 }